@@ -0,0 +1,186 @@
+package value
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var ErrUnknownVersion = errors.New("unknown encoding version")
+
+// binaryVersion is the version of the binary encoding. It is the first byte
+// of the encoding, so that older encodings stay readable when the format
+// changes.
+//
+// Version 1 is followed by a byte of flags, and the gob encoding of the value
+// if it is set, not null and not a nil pointer.
+const binaryVersion = 1
+
+const (
+	flagSet byte = 1 << iota
+	flagNull
+	flagPayload
+)
+
+// MarshalBinary implements encoding.BinaryMarshaler. Unlike the gob encoding
+// of the struct, the set state is preserved.
+func (v Value[T]) MarshalBinary() ([]byte, error) {
+	return marshalBinary(v.value, !v.IsZero(), false)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. The rules attached
+// to the value are enforced.
+func (v *Value[T]) UnmarshalBinary(b []byte) error {
+	var t T
+	set, _, err := unmarshalBinary(b, &t)
+	if err != nil {
+		return err
+	}
+
+	if !set {
+		v.reset()
+		return nil
+	}
+
+	return v.Set(t)
+}
+
+// GobEncode implements gob.GobEncoder with MarshalBinary.
+func (v Value[T]) GobEncode() ([]byte, error) {
+	return v.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder with UnmarshalBinary.
+func (v *Value[T]) GobDecode(b []byte) error {
+	return v.UnmarshalBinary(b)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. Unlike the gob encoding
+// of the struct, the set state is preserved.
+func (o *Object[T]) MarshalBinary() ([]byte, error) {
+	if o.IsZero() {
+		return marshalBinary(nil, false, false)
+	}
+
+	return marshalBinary(o.value, true, false)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. Like UnmarshalJSON,
+// the object is not validated.
+func (o *Object[T]) UnmarshalBinary(b []byte) error {
+	var t T
+	set, _, err := unmarshalBinary(b, &t)
+	if err != nil {
+		return err
+	}
+
+	o.value = t
+	o.dirty = set
+
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder with MarshalBinary.
+func (o *Object[T]) GobEncode() ([]byte, error) {
+	return o.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder with UnmarshalBinary.
+func (o *Object[T]) GobDecode(b []byte) error {
+	return o.UnmarshalBinary(b)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The undefined, null and
+// present states are preserved.
+func (n Nullable[T]) MarshalBinary() ([]byte, error) {
+	return marshalBinary(n.value, !n.IsZero(), n.null)
+}
+
+func (n *Nullable[T]) UnmarshalBinary(b []byte) error {
+	var t T
+	set, null, err := unmarshalBinary(b, &t)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case !set:
+		n.Unset()
+	case null:
+		n.SetNull()
+	default:
+		return n.Set(t)
+	}
+
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder with MarshalBinary.
+func (n Nullable[T]) GobEncode() ([]byte, error) {
+	return n.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder with UnmarshalBinary.
+func (n *Nullable[T]) GobDecode(b []byte) error {
+	return n.UnmarshalBinary(b)
+}
+
+func marshalBinary(v any, set, null bool) ([]byte, error) {
+	var flags byte
+	if set {
+		flags |= flagSet
+	}
+
+	if null {
+		flags |= flagNull
+	}
+
+	// gob cannot encode nil pointers, so they are encoded without a payload
+	// and decoded as the zero value.
+	hasPayload := set && !null && !isNilPointer(v)
+	if hasPayload {
+		flags |= flagPayload
+	}
+
+	buf := bytes.NewBuffer([]byte{binaryVersion, flags})
+	if hasPayload {
+		if err := gob.NewEncoder(buf).Encode(v); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// unmarshalBinary decodes the payload into dst, and returns the set and null
+// states.
+func unmarshalBinary(b []byte, dst any) (set, null bool, err error) {
+	if len(b) == 0 {
+		return false, false, fmt.Errorf("%w: empty input", ErrInvalidValue)
+	}
+
+	if b[0] != binaryVersion {
+		return false, false, fmt.Errorf("%w: %d", ErrUnknownVersion, b[0])
+	}
+
+	if len(b) < 2 {
+		return false, false, fmt.Errorf("%w: missing flags", ErrInvalidValue)
+	}
+
+	flags := b[1]
+	if flags&flagPayload != 0 {
+		if err := gob.NewDecoder(bytes.NewReader(b[2:])).Decode(dst); err != nil {
+			return false, false, err
+		}
+	}
+
+	return flags&flagSet != 0, flags&flagNull != 0, nil
+}
+
+func isNilPointer(v any) bool {
+	rv := reflect.ValueOf(v)
+
+	return !rv.IsValid() || (rv.Kind() == reflect.Pointer && rv.IsNil())
+}
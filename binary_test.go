@@ -0,0 +1,107 @@
+package value_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"testing"
+
+	"github.com/alextanhongpin/value"
+	"github.com/alextanhongpin/value/rules"
+)
+
+type cachedUser struct {
+	Name     *value.Value[string]
+	Score    value.Value[int]
+	Age      *value.Object[*age]
+	Nickname value.Nullable[string]
+	Unset    *value.Value[string]
+}
+
+func TestGob(t *testing.T) {
+	t.Parallel()
+
+	in := cachedUser{
+		Name:     value.New("john"),
+		Score:    *value.New(0),
+		Age:      value.NewObject(newAge(-1)),
+		Nickname: value.Null[string](),
+		Unset:    &value.Value[string]{},
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatalf("failed to encode: %s", err)
+	}
+
+	var out cachedUser
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatalf("failed to decode: %s", err)
+	}
+
+	if out.Name.MustGet() != "john" {
+		t.Fatalf("expected john, got %s", out.Name)
+	}
+
+	// Zero values stay set, and invalid objects are decoded leniently.
+	if score, ok := out.Score.Get(); !ok || score != 0 {
+		t.Fatalf("expected score to be set to 0, got %s", &out.Score)
+	}
+
+	if a := out.Age.MustGet(); *a != -1 {
+		t.Fatalf("expected -1, got %d", *a)
+	}
+
+	if !out.Nickname.IsNull() {
+		t.Fatalf("expected null, got %s", out.Nickname)
+	}
+
+	if !out.Unset.IsZero() {
+		t.Fatalf("expected not set, got %s", out.Unset)
+	}
+}
+
+func TestBinary(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rules", func(t *testing.T) {
+		t.Parallel()
+
+		b, err := value.New("johnny").MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		name := value.New("", value.Rules(rules.MaxLen[string](4)))
+		if err := name.UnmarshalBinary(b); !errors.Is(err, value.ErrInvalidValue) {
+			t.Fatalf("expected %s, got %v", value.ErrInvalidValue, err)
+		}
+	})
+
+	t.Run("version", func(t *testing.T) {
+		t.Parallel()
+
+		var v value.Value[string]
+		if err := v.UnmarshalBinary([]byte{99, 1}); !errors.Is(err, value.ErrUnknownVersion) {
+			t.Fatalf("expected %s, got %v", value.ErrUnknownVersion, err)
+		}
+	})
+
+	t.Run("nil object", func(t *testing.T) {
+		t.Parallel()
+
+		b, err := value.NewObject[*age](nil).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var o value.Object[*age]
+		if err := o.UnmarshalBinary(b); err != nil {
+			t.Fatalf("failed to unmarshal: %s", err)
+		}
+
+		if o.IsZero() || o.Valid() {
+			t.Fatalf("expected nil object to be set and invalid, got %v", o)
+		}
+	})
+}
@@ -0,0 +1,408 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	pathpkg "path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// imports are the packages used by the templates, keyed by name.
+var imports = map[string]string{
+	"errors":  "errors",
+	"fmt":     "fmt",
+	"json":    "encoding/json",
+	"strconv": "strconv",
+	"value":   "github.com/alextanhongpin/value",
+}
+
+// generate returns the formatted source of the generated file.
+func generate(pkg *Package) ([]byte, error) {
+	var body bytes.Buffer
+	for _, t := range pkg.Types {
+		var err error
+		switch t.Directive {
+		case "object":
+			err = generateObject(&body, pkg, t)
+		case "enum":
+			err = generateEnum(&body, pkg, t)
+		default:
+			err = fmt.Errorf("%s: unknown directive %q", t.Name, directivePrefix+t.Directive)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by valuegen. DO NOT EDIT.\n\npackage %s\n\n", pkg.Name)
+
+	used := make(map[string]string)
+	for _, m := range []map[string]string{imports, pkg.imports} {
+		for name, path := range m {
+			if regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\.`).Match(body.Bytes()) {
+				used[path] = name
+			}
+		}
+	}
+
+	// Standard library imports are grouped before the others.
+	var std, other []string
+	for path, name := range used {
+		spec := strconv.Quote(path)
+		if name != pathpkg.Base(path) {
+			spec = name + " " + spec
+		}
+
+		if first, _, _ := strings.Cut(path, "/"); strings.Contains(first, ".") {
+			other = append(other, spec)
+		} else {
+			std = append(std, spec)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+
+	if len(used) > 0 {
+		src.WriteString("import (\n")
+		for _, spec := range std {
+			fmt.Fprintf(&src, "\t%s\n", spec)
+		}
+		if len(std) > 0 && len(other) > 0 {
+			src.WriteString("\n")
+		}
+		for _, spec := range other {
+			fmt.Fprintf(&src, "\t%s\n", spec)
+		}
+		src.WriteString(")\n")
+	}
+
+	src.Write(body.Bytes())
+
+	b, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w\n%s", err, src.Bytes())
+	}
+
+	return b, nil
+}
+
+func generateObject(buf *bytes.Buffer, pkg *Package, t *Type) error {
+	data := struct {
+		*Type
+		Constructor string
+		Format      string
+		Parse       string
+	}{
+		Type:        t,
+		Constructor: "New" + t.Name,
+	}
+
+	if pkg.funcs[data.Constructor] {
+		data.Constructor = ""
+	}
+
+	if !t.IsStruct() {
+		data.Format = formatExpr(t.Basic, t.Recv())
+		data.Parse = parseExpr(t.Basic)
+	}
+
+	tmpl := structTemplate
+	if !t.IsStruct() {
+		tmpl = basicTemplate
+	}
+
+	return tmpl.Execute(buf, data)
+}
+
+func generateEnum(buf *bytes.Buffer, pkg *Package, t *Type) error {
+	if t.IsStruct() {
+		return fmt.Errorf("%s: enum must have a basic underlying type", t.Name)
+	}
+
+	if len(t.Values) == 0 {
+		return fmt.Errorf("%s: enum has no constants", t.Name)
+	}
+
+	data := struct {
+		*Type
+		Enum     string
+		ValuesFn string
+		ParseFn  string
+		Format   string
+	}{
+		Type:     t,
+		Enum:     "_" + t.Name + "Enum",
+		ValuesFn: t.Name + "Values",
+		ParseFn:  "Parse" + t.Name,
+		// Non-string enums are formatted by name.
+		Format: fmt.Sprintf("%s.String()", t.Recv()),
+	}
+
+	if t.Basic == "string" {
+		data.Format = formatExpr(t.Basic, t.Recv())
+	}
+
+	if pkg.funcs[data.ValuesFn] {
+		data.ValuesFn = ""
+	}
+
+	if pkg.funcs[data.ParseFn] {
+		return fmt.Errorf("%s: %s is declared, but is required by the enum", t.Name, data.ParseFn)
+	}
+
+	return enumTemplate.Execute(buf, data)
+}
+
+// formatExpr returns the expression that formats the receiver as text.
+func formatExpr(basic, recv string) string {
+	switch {
+	case basic == "string":
+		return fmt.Sprintf("string(%s)", recv)
+	case basic == "bool":
+		return fmt.Sprintf("strconv.FormatBool(bool(%s))", recv)
+	case strings.HasPrefix(basic, "int"):
+		return fmt.Sprintf("strconv.FormatInt(int64(%s), 10)", recv)
+	case strings.HasPrefix(basic, "uint"):
+		return fmt.Sprintf("strconv.FormatUint(uint64(%s), 10)", recv)
+	default:
+		return fmt.Sprintf("strconv.FormatFloat(float64(%s), 'g', -1, %s)", recv, bitSize(basic, "float"))
+	}
+}
+
+// parseExpr returns the expression that parses the text into a value of
+// the basic type, and an error. Strings are converted directly.
+func parseExpr(basic string) string {
+	switch {
+	case basic == "string":
+		return ""
+	case basic == "bool":
+		return "strconv.ParseBool(string(text))"
+	case strings.HasPrefix(basic, "int"):
+		return fmt.Sprintf("strconv.ParseInt(string(text), 10, %s)", bitSize(basic, "int"))
+	case strings.HasPrefix(basic, "uint"):
+		return fmt.Sprintf("strconv.ParseUint(string(text), 10, %s)", bitSize(basic, "uint"))
+	default:
+		return fmt.Sprintf("strconv.ParseFloat(string(text), %s)", bitSize(basic, "float"))
+	}
+}
+
+func bitSize(basic, prefix string) string {
+	if size := strings.TrimPrefix(basic, prefix); size != "" {
+		return size
+	}
+
+	return "0"
+}
+
+var structTemplate = template.Must(template.New("struct").Parse(`
+{{- with .Constructor}}
+func {{.}}({{range $i, $f := $.Fields}}{{if $i}}, {{end}}{{$f.Param}} {{$f.Type}}{{end}}) *{{$.Name}} {
+	return &{{$.Name}}{
+	{{- range $.Fields}}
+		{{.Name}}: {{.Param}},
+	{{- end}}
+	}
+}
+{{end}}
+{{- if not (.Has "Validate")}}
+func ({{.Recv}} *{{.Name}}) Validate() error {
+	if {{.Recv}} == nil {
+		return value.ErrNotSet
+	}
+
+	return value.ValidateStruct({{.Recv}})
+}
+{{end}}
+{{- if not (.Has "Valid")}}
+func ({{.Recv}} *{{.Name}}) Valid() bool {
+	return {{.Recv}}.Validate() == nil
+}
+{{end}}
+{{- if not (.Has "MustValidate")}}
+func ({{.Recv}} *{{.Name}}) MustValidate() {
+	if err := {{.Recv}}.Validate(); err != nil {
+		panic(err)
+	}
+}
+{{end}}
+{{- if not (.Has "Errors")}}
+// Errors returns the validation errors keyed by the field path.
+func ({{.Recv}} *{{.Name}}) Errors() map[string]error {
+	err := {{.Recv}}.Validate()
+	if err == nil {
+		return nil
+	}
+
+	var errs value.ErrorMap
+	if errors.As(err, &errs) {
+		return errs
+	}
+
+	return map[string]error{"": err}
+}
+{{end}}
+{{- if not (.Has "String")}}
+func ({{.Recv}} {{.Name}}) String() string {
+	type plain {{.Name}}
+	return fmt.Sprintf("%+v", plain({{.Recv}}))
+}
+{{end}}
+`))
+
+var basicTemplate = template.Must(template.New("basic").Parse(`
+{{- with .Constructor}}
+func {{.}}(v {{$.Basic}}) *{{$.Name}} {
+	{{$.Recv}} := {{$.Name}}(v)
+	return &{{$.Recv}}
+}
+{{end}}
+{{- if not (.Has "Validate")}}
+func ({{.Recv}} *{{.Name}}) Validate() error {
+	if {{.Recv}} == nil {
+		return value.ErrNotSet
+	}
+
+	return nil
+}
+{{end}}
+{{- if not (.Has "Valid")}}
+func ({{.Recv}} *{{.Name}}) Valid() bool {
+	return {{.Recv}}.Validate() == nil
+}
+{{end}}
+{{- if not (.Has "MustValidate")}}
+func ({{.Recv}} *{{.Name}}) MustValidate() {
+	if err := {{.Recv}}.Validate(); err != nil {
+		panic(err)
+	}
+}
+{{end}}
+{{- if not (.Has "String")}}
+func ({{.Recv}} {{.Name}}) String() string {
+	return {{.Format}}
+}
+{{end}}
+{{- if not (.Has "MarshalText")}}
+func ({{.Recv}} {{.Name}}) MarshalText() ([]byte, error) {
+	return []byte({{.Format}}), nil
+}
+{{end}}
+{{- if not (.Has "UnmarshalText")}}
+func ({{.Recv}} *{{.Name}}) UnmarshalText(text []byte) error {
+	{{- if eq .Basic "string"}}
+	*{{.Recv}} = {{.Name}}(text)
+
+	return nil
+	{{- else}}
+	v, err := {{.Parse}}
+	if err != nil {
+		return err
+	}
+
+	*{{.Recv}} = {{.Name}}(v)
+
+	return nil
+	{{- end}}
+}
+{{end}}
+{{- if not (.Has "MarshalJSON")}}
+func ({{.Recv}} {{.Name}}) MarshalJSON() ([]byte, error) {
+	return json.Marshal({{.Basic}}({{.Recv}}))
+}
+{{end}}
+{{- if not (.Has "UnmarshalJSON")}}
+func ({{.Recv}} *{{.Name}}) UnmarshalJSON(raw []byte) error {
+	var v {{.Basic}}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return err
+	}
+
+	*{{.Recv}} = {{.Name}}(v)
+
+	return nil
+}
+{{end}}
+`))
+
+var enumTemplate = template.Must(template.New("enum").Parse(`
+var {{.Enum}} = value.NewEnum(
+{{- range .Values}}
+	{{.}},
+{{- end}}
+)
+{{with .ValuesFn}}
+// {{.}} returns the allowed values of {{$.Name}}.
+func {{.}}() []{{$.Name}} {
+	return {{$.Enum}}.Values()
+}
+{{end}}
+// {{.ParseFn}} returns the {{.Name}} whose text form is s.
+func {{.ParseFn}}(s string) ({{.Name}}, error) {
+	return {{.Enum}}.Parse(s)
+}
+{{if not (.Has "Validate")}}
+func ({{.Recv}} *{{.Name}}) Validate() error {
+	if {{.Recv}} == nil {
+		return value.ErrNotSet
+	}
+
+	return {{.Enum}}.Validate(*{{.Recv}})
+}
+{{end}}
+{{- if not (.Has "Valid")}}
+func ({{.Recv}} *{{.Name}}) Valid() bool {
+	return {{.Recv}}.Validate() == nil
+}
+{{end}}
+{{- if not (.Has "MustValidate")}}
+func ({{.Recv}} *{{.Name}}) MustValidate() {
+	if err := {{.Recv}}.Validate(); err != nil {
+		panic(err)
+	}
+}
+{{end}}
+{{- if not (.Has "String")}}
+func ({{.Recv}} {{.Name}}) String() string {
+	{{- if eq .Basic "string"}}
+	return string({{.Recv}})
+	{{- else}}
+	switch {{.Recv}} {
+	{{- range .Values}}
+	case {{.}}:
+		return "{{.}}"
+	{{- end}}
+	default:
+		return fmt.Sprintf("{{.Name}}(%v)", {{.Basic}}({{.Recv}}))
+	}
+	{{- end}}
+}
+{{end}}
+{{- if not (.Has "MarshalText")}}
+func ({{.Recv}} {{.Name}}) MarshalText() ([]byte, error) {
+	return []byte({{.Format}}), nil
+}
+{{end}}
+{{- if not (.Has "UnmarshalText")}}
+func ({{.Recv}} *{{.Name}}) UnmarshalText(text []byte) error {
+	v, err := {{.ParseFn}}(string(text))
+	if err != nil {
+		return err
+	}
+
+	*{{.Recv}} = v
+
+	return nil
+}
+{{end}}
+{{- if not (.Has "JSONSchema")}}
+func ({{.Name}}) JSONSchema() map[string]any {
+	return {{.Enum}}.JSONSchema()
+}
+{{end}}
+`))
@@ -0,0 +1,75 @@
+// Command valuegen generates the boilerplate for value objects.
+//
+// Annotate the type declarations with a directive, and run valuegen with
+// go generate in the package directory:
+//
+//	//go:generate go run github.com/alextanhongpin/value/cmd/valuegen
+//
+//	//valuegen:object
+//	type Email string
+//
+//	//valuegen:object
+//	type CreateUserDto struct {
+//		Name  *value.Value[string]  `json:"name"`
+//		Email *value.Object[*Email] `json:"email"`
+//	}
+//
+// For struct types, valuegen generates a constructor, Validate (using
+// value.ValidateStruct), Valid, MustValidate, Errors and String. For types
+// with a basic underlying type, it generates a constructor, Validate, Valid,
+// MustValidate, String, and the text and JSON marshalers.
+//
+// The enum directive generates an enum from the constants and variables
+// declared with the type, backed by value.Enum:
+//
+//	//valuegen:enum
+//	type Unit string
+//
+//	const (
+//		UnitMM Unit = "mm"
+//		UnitCM Unit = "cm"
+//	)
+//
+// It generates UnitValues, ParseUnit, Validate, Valid, MustValidate, String,
+// the text marshalers and JSONSchema. Enums with a non-string underlying type
+// are formatted and parsed by their constant names.
+//
+// Methods and constructors that are already declared are not generated, so
+// hand-written Validate methods take precedence.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "package directory")
+	output := flag.String("output", "value_gen.go", "output file name, relative to the package directory")
+	flag.Parse()
+
+	if err := run(*dir, *output); err != nil {
+		fmt.Fprintf(os.Stderr, "valuegen: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir, output string) error {
+	pkg, err := parseDir(dir, output)
+	if err != nil {
+		return err
+	}
+
+	if len(pkg.Types) == 0 {
+		return fmt.Errorf("no annotated types in %s", dir)
+	}
+
+	b, err := generate(pkg)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, output), b, 0o644)
+}
@@ -0,0 +1,337 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	pathpkg "path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const directivePrefix = "//valuegen:"
+
+// Package is the parsed package that the code is generated for.
+type Package struct {
+	Name  string
+	Types []*Type
+	// funcs are the package-level functions declared by hand.
+	funcs map[string]bool
+	// imports maps the import names to the paths, for the field types that
+	// are copied into the generated code.
+	imports map[string]string
+}
+
+// Type is an annotated type declaration.
+type Type struct {
+	Name      string
+	Directive string
+	// Basic is the underlying type of non-struct types, e.g. "string".
+	Basic  string
+	Fields []Field
+	// Values are the names of the constants and variables declared with the
+	// type, in order.
+	Values []string
+	// methods are the methods declared by hand, which are not generated.
+	methods map[string]bool
+}
+
+type Field struct {
+	Name  string
+	Param string
+	Type  string
+}
+
+func (t *Type) IsStruct() bool {
+	return t.Basic == ""
+}
+
+// Recv returns the receiver name, e.g. "e" for Email.
+func (t *Type) Recv() string {
+	return strings.ToLower(t.Name[:1])
+}
+
+func (t *Type) Has(method string) bool {
+	return t.methods[method]
+}
+
+// parseDir parses the non-test Go files in the directory, skipping the
+// generated output file.
+func parseDir(dir, output string) (*Package, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || name == output {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+
+		if isGenerated(f) {
+			continue
+		}
+
+		files = append(files, f)
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files in %s", dir)
+	}
+
+	return parseFiles(fset, files)
+}
+
+func parseFiles(fset *token.FileSet, files []*ast.File) (*Package, error) {
+	pkg := &Package{
+		Name:    files[0].Name.Name,
+		funcs:   make(map[string]bool),
+		imports: make(map[string]string),
+	}
+
+	types := make(map[string]*Type)
+	methods := make(map[string]map[string]bool)
+	values := make(map[string][]string)
+	for _, f := range files {
+		for _, spec := range f.Imports {
+			path := strings.Trim(spec.Path.Value, `"`)
+			name := pathpkg.Base(path)
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+			pkg.imports[name] = path
+		}
+
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if d.Recv == nil {
+					pkg.funcs[d.Name.Name] = true
+					continue
+				}
+
+				recv := receiverName(d.Recv.List[0].Type)
+				if methods[recv] == nil {
+					methods[recv] = make(map[string]bool)
+				}
+				methods[recv][d.Name.Name] = true
+			case *ast.GenDecl:
+				if d.Tok == token.CONST || d.Tok == token.VAR {
+					collectValues(values, d)
+					continue
+				}
+
+				if d.Tok != token.TYPE {
+					continue
+				}
+
+				for _, spec := range d.Specs {
+					ts := spec.(*ast.TypeSpec)
+					directive := findDirective(ts.Doc, d.Doc)
+					if directive == "" {
+						continue
+					}
+
+					t, err := parseType(fset, ts, directive)
+					if err != nil {
+						return nil, err
+					}
+
+					types[t.Name] = t
+					pkg.Types = append(pkg.Types, t)
+				}
+			}
+		}
+	}
+
+	for name, t := range types {
+		t.methods = methods[name]
+		t.Values = values[name]
+	}
+
+	sort.Slice(pkg.Types, func(i, j int) bool {
+		return pkg.Types[i].Name < pkg.Types[j].Name
+	})
+
+	return pkg, nil
+}
+
+func parseType(fset *token.FileSet, ts *ast.TypeSpec, directive string) (*Type, error) {
+	if ts.TypeParams != nil {
+		return nil, fmt.Errorf("%s: generic types are not supported", ts.Name.Name)
+	}
+
+	t := &Type{
+		Name:      ts.Name.Name,
+		Directive: directive,
+	}
+
+	switch typ := ts.Type.(type) {
+	case *ast.Ident:
+		if !isBasic(typ.Name) {
+			return nil, fmt.Errorf("%s: unsupported underlying type %s", t.Name, typ.Name)
+		}
+
+		t.Basic = typ.Name
+	case *ast.StructType:
+		for _, f := range typ.Fields.List {
+			expr, err := exprString(fset, f.Type)
+			if err != nil {
+				return nil, err
+			}
+
+			names := f.Names
+			if len(names) == 0 {
+				// Embedded field.
+				names = []*ast.Ident{ast.NewIdent(receiverName(f.Type))}
+			}
+
+			for _, n := range names {
+				if !n.IsExported() {
+					continue
+				}
+
+				t.Fields = append(t.Fields, Field{
+					Name:  n.Name,
+					Param: paramName(n.Name),
+					Type:  expr,
+				})
+			}
+		}
+	default:
+		return nil, fmt.Errorf("%s: unsupported type", t.Name)
+	}
+
+	return t, nil
+}
+
+// collectValues collects the names declared with an explicit type, keyed by
+// the type name. Constants without a type and value inherit the type of the
+// previous constant, as with iota.
+func collectValues(values map[string][]string, d *ast.GenDecl) {
+	var typ string
+	for _, spec := range d.Specs {
+		vs := spec.(*ast.ValueSpec)
+		switch {
+		case vs.Type != nil:
+			typ = receiverName(vs.Type)
+		case d.Tok != token.CONST || len(vs.Values) > 0:
+			typ = ""
+		}
+
+		if typ == "" {
+			continue
+		}
+
+		for _, n := range vs.Names {
+			if n.Name != "_" {
+				values[typ] = append(values[typ], n.Name)
+			}
+		}
+	}
+}
+
+// isGenerated reports whether the file has the standard generated code header.
+func isGenerated(f *ast.File) bool {
+	for _, cg := range f.Comments {
+		if cg.Pos() > f.Package {
+			return false
+		}
+
+		for _, c := range cg.List {
+			if strings.HasPrefix(c.Text, "// Code generated ") && strings.HasSuffix(c.Text, " DO NOT EDIT.") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func findDirective(docs ...*ast.CommentGroup) string {
+	for _, doc := range docs {
+		if doc == nil {
+			continue
+		}
+
+		for _, c := range doc.List {
+			if strings.HasPrefix(c.Text, directivePrefix) {
+				return strings.TrimSpace(strings.TrimPrefix(c.Text, directivePrefix))
+			}
+		}
+	}
+
+	return ""
+}
+
+func receiverName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(e.X)
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.IndexExpr:
+		return receiverName(e.X)
+	case *ast.IndexListExpr:
+		return receiverName(e.X)
+	case *ast.Ident:
+		return e.Name
+	default:
+		return ""
+	}
+}
+
+func exprString(fset *token.FileSet, expr ast.Expr) (string, error) {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, expr); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+func paramName(field string) string {
+	runes := []rune(field)
+	// Lowercase the leading initialism, e.g. "ID" to "id" and "URLPath" to
+	// "urlPath".
+	for i := 0; i < len(runes) && unicode.IsUpper(runes[i]); i++ {
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+
+		runes[i] = unicode.ToLower(runes[i])
+	}
+
+	name := string(runes)
+	if token.IsKeyword(name) {
+		return name + "_"
+	}
+
+	return name
+}
+
+func isBasic(name string) bool {
+	switch name {
+	case "string", "bool",
+		"int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64",
+		"float32", "float64":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,25 @@
+package enum
+
+//go:generate go run github.com/alextanhongpin/value/cmd/valuegen
+
+//valuegen:enum
+type Unit string
+
+var (
+	UnitMM Unit = "mm"
+	UnitCM Unit = "cm"
+	UnitM  Unit = "m"
+)
+
+//valuegen:enum
+type Weekday int
+
+const (
+	Sunday Weekday = iota
+	Monday
+	Tuesday
+	_
+	Thursday
+)
+
+const MaxDays = 7
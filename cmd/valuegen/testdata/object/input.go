@@ -0,0 +1,41 @@
+package object
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/alextanhongpin/value"
+)
+
+//go:generate go run github.com/alextanhongpin/value/cmd/valuegen
+
+var ErrInvalidEmailFormat = errors.New("invalid email format")
+
+//valuegen:object
+type Email string
+
+func (e *Email) Validate() error {
+	if e == nil || *e == "" {
+		return value.ErrNotSet
+	}
+
+	if !strings.Contains(string(*e), "@") {
+		return ErrInvalidEmailFormat
+	}
+
+	return nil
+}
+
+//valuegen:object
+type Age uint8
+
+//valuegen:object
+type CreateUserDto struct {
+	ID        *value.Value[string]  `json:"id"`
+	Email     *value.Object[*Email] `json:"email"`
+	Age       *value.Object[*Age]   `json:"age" value:"optional"`
+	Type      string                `json:"type"`
+	CreatedAt time.Time             `json:"createdAt"`
+	internal  bool
+}
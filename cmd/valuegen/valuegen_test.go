@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestGenerate(t *testing.T) {
+	dirs, err := filepath.Glob("testdata/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, dir := range dirs {
+		dir := dir
+
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			pkg, err := parseDir(dir, "")
+			if err != nil {
+				t.Fatalf("failed to parse: %s", err)
+			}
+
+			got, err := generate(pkg)
+			if err != nil {
+				t.Fatalf("failed to generate: %s", err)
+			}
+
+			golden := filepath.Join(dir, "output.golden")
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("failed to read golden file: %s", err)
+			}
+
+			if !bytes.Equal(want, got) {
+				t.Fatalf("generated code does not match %s, run go test -update\n%s", golden, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,404 @@
+// Package config loads application settings into structs with Value and
+// Object fields, so that a setting that is not set is told apart from a
+// setting that is set to the zero value.
+//
+//	type Config struct {
+//		Port     *value.Value[int]    `env:"PORT" default:"8080"`
+//		Database struct {
+//			URL *value.Value[string] // DATABASE_URL
+//		}
+//		Debug *value.Value[bool] `value:"optional"`
+//	}
+//
+//	err := config.Load(&cfg, config.Prefix("APP_"), config.File(".env"))
+//
+// The settings are loaded from the defaults, the files and the environment,
+// with the later sources taking precedence. The struct is then validated with
+// value.ValidateStruct, so fields are required unless their `value` tag says
+// otherwise.
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/alextanhongpin/value"
+)
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// Option configures Load.
+type Option func(*loader)
+
+// Prefix is prepended to the names of the environment variables.
+func Prefix(prefix string) Option {
+	return func(l *loader) {
+		l.prefix = prefix
+	}
+}
+
+// File loads the settings from the file. Files ending in .json are decoded
+// as JSON, with the fields named after their json tags. Other files are read
+// as KEY=value lines, with the keys named like the environment variables.
+func File(path string) Option {
+	return func(l *loader) {
+		l.files = append(l.files, path)
+	}
+}
+
+// LookupEnv replaces os.LookupEnv.
+func LookupEnv(lookup func(string) (string, bool)) Option {
+	return func(l *loader) {
+		l.lookupEnv = lookup
+	}
+}
+
+type loader struct {
+	prefix    string
+	files     []string
+	lookupEnv func(string) (string, bool)
+}
+
+// setting is a field that is loaded from text.
+type setting struct {
+	// name is the name of the environment variable.
+	name string
+	// path is the json path of the field, as reported by ValidateStruct.
+	path  string
+	field reflect.Value
+	// def is the value of the `default` tag.
+	def    string
+	hasDef bool
+}
+
+// Load loads the settings into v, which must be a pointer to a struct.
+//
+// Only the fields that implement encoding.TextUnmarshaler, like Value and
+// Object, are loaded. Nested structs are walked, with the name of the struct
+// prefixing the names of its fields. Environment variables are named with the
+// `env` tag, and default to the upper snake case of the json name or the field
+// name. Empty environment variables are ignored.
+//
+// Every missing and invalid setting is reported at once, as a value.ErrorMap
+// keyed by the name of the environment variable.
+func Load(v any, opts ...Option) error {
+	l := &loader{lookupEnv: os.LookupEnv}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %T", value.ErrNotStruct, v)
+	}
+
+	settings := collect(rv.Elem(), l.prefix, "")
+	errs := make(value.ErrorMap)
+
+	for _, s := range settings {
+		if s.hasDef {
+			setText(errs, s, s.def)
+		}
+	}
+
+	for _, path := range l.files {
+		if err := l.loadFile(path, v, settings, errs); err != nil {
+			errs[path] = value.WithField(path, err)
+		}
+	}
+
+	for _, s := range settings {
+		if text, ok := l.lookupEnv(s.name); ok && text != "" {
+			setText(errs, s, text)
+		}
+	}
+
+	validate(v, settings, errs)
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+func (l *loader) loadFile(path string, v any, settings []setting, errs value.ErrorMap) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if filepath.Ext(path) == ".json" {
+		if err := json.Unmarshal(b, v); err != nil {
+			return err
+		}
+
+		// The settings in the file replace the errors of an earlier source.
+		var doc map[string]any
+		if err := json.Unmarshal(b, &doc); err != nil {
+			return err
+		}
+
+		for _, s := range settings {
+			if hasPath(doc, s.path) {
+				delete(errs, s.name)
+			}
+		}
+
+		return nil
+	}
+
+	env, err := parseEnv(b)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range settings {
+		if text, ok := env[s.name]; ok && text != "" {
+			setText(errs, s, text)
+		}
+	}
+
+	return nil
+}
+
+func setText(errs value.ErrorMap, s setting, text string) {
+	f := s.field
+	if f.Kind() == reflect.Pointer {
+		if f.IsNil() {
+			f.Set(reflect.New(f.Type().Elem()))
+		}
+	} else {
+		f = f.Addr()
+	}
+
+	// A valid setting from a later source replaces the error of an earlier one.
+	if err := f.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text)); err != nil {
+		errs[s.name] = value.WithField(s.name, err)
+	} else {
+		delete(errs, s.name)
+	}
+}
+
+// validate validates the struct, and renames the errors of the settings after
+// their environment variables. Settings that failed to load keep their error.
+func validate(v any, settings []setting, errs value.ErrorMap) {
+	err := value.ValidateStruct(v)
+	if err == nil {
+		return
+	}
+
+	names := make(map[string]string, len(settings))
+	for _, s := range settings {
+		names[s.path] = s.name
+	}
+
+	m, ok := err.(value.ErrorMap)
+	if !ok {
+		errs[""] = err
+		return
+	}
+
+	for path, err := range m {
+		name, found := names[path]
+		if !found {
+			errs[path] = err
+			continue
+		}
+
+		if _, failed := errs[name]; failed {
+			continue
+		}
+
+		errs[name] = rename(name, err)
+	}
+}
+
+// rename sets the path of the field errors to the name.
+func rename(name string, err error) error {
+	switch e := err.(type) {
+	case *value.FieldError:
+		return &value.FieldError{Path: name, Code: e.Code, Err: e.Err}
+	case value.Errors:
+		errs := make(value.Errors, len(e))
+		for i, err := range e {
+			errs[i] = rename(name, err)
+		}
+
+		return errs
+	default:
+		return err
+	}
+}
+
+// collect lists the settings of the struct. Pointers to nested structs are
+// allocated.
+func collect(v reflect.Value, prefix, path string) []setting {
+	var settings []setting
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+
+		jsonName, ok := jsonName(f)
+		if !ok {
+			continue
+		}
+
+		name := f.Tag.Get("env")
+		if name == "" {
+			name = snakeCase(jsonName)
+		}
+
+		fv := v.Field(i)
+		switch {
+		case isText(f.Type):
+			if !f.IsExported() {
+				continue
+			}
+
+			def, hasDef := f.Tag.Lookup("default")
+			settings = append(settings, setting{
+				name:   prefix + name,
+				path:   joinPath(path, jsonName),
+				field:  fv,
+				def:    def,
+				hasDef: hasDef,
+			})
+		case f.Anonymous && f.Type.Kind() == reflect.Struct && f.Tag.Get("json") == "":
+			settings = append(settings, collect(fv, prefix, path)...)
+		case f.Type.Kind() == reflect.Struct && f.IsExported():
+			settings = append(settings, collect(fv, prefix+name+"_", joinPath(path, jsonName))...)
+		case f.Type.Kind() == reflect.Pointer && f.Type.Elem().Kind() == reflect.Struct && f.IsExported():
+			if fv.IsNil() {
+				fv.Set(reflect.New(f.Type.Elem()))
+			}
+
+			settings = append(settings, collect(fv.Elem(), prefix+name+"_", joinPath(path, jsonName))...)
+		}
+	}
+
+	return settings
+}
+
+func isText(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		return t.Implements(textUnmarshalerType)
+	}
+
+	return reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+func jsonName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name, true
+	}
+
+	return f.Name, true
+}
+
+// hasPath reports whether the decoded JSON object has the json path. Like
+// encoding/json, the keys are matched case-insensitively.
+func hasPath(doc map[string]any, path string) bool {
+	var v any = doc
+	for _, key := range strings.Split(path, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return false
+		}
+
+		v, ok = lookupKey(m, key)
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func lookupKey(m map[string]any, key string) (any, bool) {
+	if v, ok := m[key]; ok {
+		return v, true
+	}
+
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
+
+func joinPath(parent, child string) string {
+	if parent == "" {
+		return child
+	}
+
+	return parent + "." + child
+}
+
+// snakeCase converts the name to upper snake case, e.g. "postalCode" and
+// "PostalCode" to "POSTAL_CODE", and "DatabaseURL" to "DATABASE_URL".
+func snakeCase(name string) string {
+	runes := []rune(name)
+
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			next := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && next) {
+				b.WriteByte('_')
+			}
+		}
+
+		b.WriteRune(unicode.ToUpper(r))
+	}
+
+	return b.String()
+}
+
+// parseEnv parses KEY=value lines. Blank lines and lines starting with # are
+// skipped, and the values may be quoted.
+func parseEnv(b []byte) (map[string]string, error) {
+	env := make(map[string]string)
+
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, val, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: missing =", n)
+		}
+
+		val = strings.TrimSpace(val)
+		if len(val) >= 2 && (val[0] == '"' || val[0] == '\'') && val[len(val)-1] == val[0] {
+			val = val[1 : len(val)-1]
+		}
+
+		env[strings.TrimSpace(key)] = val
+	}
+
+	return env, s.Err()
+}
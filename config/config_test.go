@@ -0,0 +1,128 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alextanhongpin/value"
+	"github.com/alextanhongpin/value/config"
+	"github.com/alextanhongpin/value/rules"
+)
+
+type database struct {
+	URL     *value.Value[string] `json:"url"`
+	MaxConn *value.Value[int]    `json:"maxConn" default:"10"`
+}
+
+type appConfig struct {
+	Port     *value.Value[int]    `json:"port" env:"PORT" default:"8080"`
+	Name     *value.Value[string] `json:"name"`
+	Debug    *value.Value[bool]   `json:"debug" value:"optional"`
+	Database database             `json:"database"`
+	Ignored  string               `json:"ignored"`
+}
+
+func env(vars map[string]string) config.Option {
+	return config.LookupEnv(func(key string) (string, bool) {
+		v, ok := vars[key]
+		return v, ok
+	})
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	t.Run("precedence", func(t *testing.T) {
+		t.Parallel()
+
+		dotenv := writeFile(t, ".env", "# comment\nAPP_NAME=\"from file\"\nexport APP_DATABASE_URL=postgres://file\n")
+		jsonFile := writeFile(t, "config.json", `{"port": 9000, "database": {"maxConn": 20}}`)
+
+		var cfg appConfig
+		err := config.Load(&cfg,
+			config.Prefix("APP_"),
+			config.File(jsonFile),
+			config.File(dotenv),
+			env(map[string]string{"APP_PORT": "3000", "APP_DATABASE_URL": "postgres://env", "APP_DEBUG": ""}),
+		)
+		if err != nil {
+			t.Fatalf("failed to load: %s", err)
+		}
+
+		if port := cfg.Port.MustGet(); port != 3000 {
+			t.Fatalf("expected 3000, got %d", port)
+		}
+
+		if name := cfg.Name.MustGet(); name != "from file" {
+			t.Fatalf("expected from file, got %s", name)
+		}
+
+		if url := cfg.Database.URL.MustGet(); url != "postgres://env" {
+			t.Fatalf("expected postgres://env, got %s", url)
+		}
+
+		if n := cfg.Database.MaxConn.MustGet(); n != 20 {
+			t.Fatalf("expected 20, got %d", n)
+		}
+
+		// Empty variables are ignored, so debug is not set rather than false.
+		if !cfg.Debug.IsZero() {
+			t.Fatalf("expected debug to be not set, got %s", cfg.Debug)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := appConfig{
+			Name: value.New("", value.Rules(rules.MinLen[string](3))),
+		}
+		err := config.Load(&cfg, env(map[string]string{"PORT": "http", "NAME": "ab"}))
+
+		var errs value.ErrorMap
+		if !errors.As(err, &errs) || len(errs) != 3 {
+			t.Fatalf("expected 3 errors, got %v", err)
+		}
+
+		for _, name := range []string{"PORT", "NAME", "DATABASE_URL"} {
+			if errs[name] == nil {
+				t.Fatalf("expected %s error, got %v", name, errs)
+			}
+		}
+
+		if !errors.Is(errs["DATABASE_URL"], value.ErrNotSet) {
+			t.Fatalf("expected %s, got %v", value.ErrNotSet, errs["DATABASE_URL"])
+		}
+	})
+	t.Run("json replaces invalid default", func(t *testing.T) {
+		t.Parallel()
+
+		type portConfig struct {
+			Port *value.Value[int] `json:"port" default:"abc"`
+		}
+
+		jsonFile := writeFile(t, "config.json", `{"port": 8080}`)
+
+		var cfg portConfig
+		if err := config.Load(&cfg, config.File(jsonFile), env(nil)); err != nil {
+			t.Fatalf("failed to load: %s", err)
+		}
+
+		if port := cfg.Port.MustGet(); port != 8080 {
+			t.Fatalf("expected 8080, got %d", port)
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package value
+
+import "fmt"
+
+// Container is implemented by Value, Object and Nullable, so that decoders in
+// other packages can populate them by reflection.
+type Container interface {
+	IsZero() bool
+	// Any returns the contained value. If it is not set, the zero value of T
+	// is returned, with pointers allocated so that it can be decoded into.
+	Any() any
+	// SetAny sets the contained value, which must be of type T. Like Set, the
+	// rules of a Value are enforced.
+	SetAny(v any) error
+}
+
+var (
+	_ Container = (*Value[int])(nil)
+	_ Container = (*Object[validatable])(nil)
+	_ Container = (*Nullable[int])(nil)
+)
+
+func (v *Value[T]) Any() any {
+	if v.IsZero() {
+		return Zero[T]()
+	}
+
+	return v.value
+}
+
+func (v *Value[T]) SetAny(a any) error {
+	t, err := assertType[T](a)
+	if err != nil {
+		return err
+	}
+
+	return v.Set(t)
+}
+
+func (o *Object[T]) Any() any {
+	if o.IsZero() {
+		return Zero[T]()
+	}
+
+	return o.value
+}
+
+// SetAny sets the contained object. Like UnmarshalJSON, the object is not
+// validated.
+func (o *Object[T]) SetAny(a any) error {
+	t, err := assertType[T](a)
+	if err != nil {
+		return err
+	}
+
+	o.value = t
+	o.dirty = true
+
+	return nil
+}
+
+// Any returns the present value. Null values return the zero value of T.
+func (n *Nullable[T]) Any() any {
+	if !n.IsPresent() {
+		return Zero[T]()
+	}
+
+	return n.value
+}
+
+func (n *Nullable[T]) SetAny(a any) error {
+	t, err := assertType[T](a)
+	if err != nil {
+		return err
+	}
+
+	return n.Set(t)
+}
+
+func assertType[T any](a any) (T, error) {
+	t, ok := a.(T)
+	if !ok {
+		return t, fmt.Errorf("%w: cannot assign %T to %T", ErrInvalidValue, a, t)
+	}
+
+	return t, nil
+}
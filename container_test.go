@@ -0,0 +1,51 @@
+package value_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alextanhongpin/value"
+	"github.com/alextanhongpin/value/rules"
+)
+
+func TestContainer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("value", func(t *testing.T) {
+		t.Parallel()
+
+		var c value.Container = value.New(0, value.Rules(rules.Min(1)))
+		if err := c.SetAny(0); !errors.Is(err, value.ErrInvalidValue) {
+			t.Fatalf("expected %s, got %v", value.ErrInvalidValue, err)
+		}
+
+		if err := c.SetAny("1"); !errors.Is(err, value.ErrInvalidValue) {
+			t.Fatalf("expected %s, got %v", value.ErrInvalidValue, err)
+		}
+
+		if err := c.SetAny(2); err != nil || c.Any() != 2 {
+			t.Fatalf("expected 2, got %v, %v", c.Any(), err)
+		}
+	})
+
+	t.Run("object", func(t *testing.T) {
+		t.Parallel()
+
+		var o value.Object[*age]
+
+		// Pointers are allocated, so that they can be decoded into.
+		a, ok := o.Any().(*age)
+		if !ok || a == nil {
+			t.Fatalf("expected allocated *age, got %v", o.Any())
+		}
+
+		*a = -1
+		if err := o.SetAny(a); err != nil {
+			t.Fatalf("expected lenient set, got %s", err)
+		}
+
+		if o.IsZero() || o.Valid() {
+			t.Fatal("expected invalid object to be set")
+		}
+	})
+}
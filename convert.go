@@ -0,0 +1,125 @@
+package value
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Zero returns the zero value of T. If T is a pointer, the pointer is
+// allocated so that it can be decoded into. It is shared with the decoders of
+// the subpackages.
+func Zero[T any]() T {
+	var t T
+	if typ := reflect.TypeOf(&t).Elem(); typ.Kind() == reflect.Pointer {
+		reflect.ValueOf(&t).Elem().Set(reflect.New(typ.Elem()))
+	}
+
+	return t
+}
+
+// target returns the value to decode into. Pointer types are decoded into
+// the value they point to.
+func target[T any](t *T) reflect.Value {
+	v := reflect.ValueOf(t).Elem()
+	if v.Kind() == reflect.Pointer {
+		return v.Elem()
+	}
+
+	return v
+}
+
+// assign assigns the src to the dst, converting between basic types. Strings
+// are parsed into dst with ParseText.
+func assign(dst reflect.Value, src any) error {
+	sv := reflect.ValueOf(src)
+	switch s := src.(type) {
+	case string:
+		return ParseText(dst, s)
+	case []byte:
+		if dst.Kind() == reflect.Slice && dst.Type().Elem().Kind() == reflect.Uint8 {
+			dst.SetBytes(append([]byte(nil), s...))
+			return nil
+		}
+
+		return ParseText(dst, string(s))
+	}
+
+	switch {
+	case sv.Type().AssignableTo(dst.Type()):
+		dst.Set(sv)
+		return nil
+	case isNumber(sv.Kind()) && isNumber(dst.Kind()), sv.Kind() == reflect.Bool && dst.Kind() == reflect.Bool:
+		dst.Set(sv.Convert(dst.Type()))
+		return nil
+	default:
+		return fmt.Errorf("%w: cannot assign %T to %s", ErrInvalidValue, src, dst.Type())
+	}
+}
+
+// ParseText parses the text into dst. If dst implements
+// encoding.TextUnmarshaler, it is used. Otherwise basic types are parsed with
+// strconv. It is shared with the decoders of the subpackages, so that text is
+// parsed the same way as by UnmarshalText.
+func ParseText(dst reflect.Value, s string) error {
+	if dst.CanAddr() {
+		if u, ok := dst.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return u.UnmarshalText([]byte(s))
+		}
+	}
+
+	switch dst.Kind() {
+	case reflect.String:
+		dst.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+
+		dst.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		dst.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 10, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		dst.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, dst.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		dst.SetFloat(n)
+	case reflect.Slice:
+		if dst.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("%w: cannot parse text into %s", ErrInvalidValue, dst.Type())
+		}
+
+		dst.SetBytes([]byte(s))
+	default:
+		return fmt.Errorf("%w: cannot parse text into %s", ErrInvalidValue, dst.Type())
+	}
+
+	return nil
+}
+
+func isNumber(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,218 @@
+package csvx_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/alextanhongpin/value"
+	"github.com/alextanhongpin/value/csvx"
+	"github.com/alextanhongpin/value/rules"
+)
+
+type address struct {
+	City       *value.Value[string] `json:"city"`
+	PostalCode *value.Value[string] `json:"postalCode"`
+}
+
+func (a *address) Validate() error {
+	return value.ValidateStruct(a)
+}
+
+type userRow struct {
+	Name    *value.Value[string]    `json:"name"`
+	Age     *value.Value[int]       `json:"age"`
+	Note    value.Nullable[string]  `json:"note" value:"optional"`
+	Address *value.Object[*address] `json:"address" value:"optional"`
+	Tags    []string                `json:"tags"`
+}
+
+// notSet returns a Value that is not set, with the rules attached.
+func notSet[T any](rules ...value.Rule[T]) *value.Value[T] {
+	v := new(value.Value[T])
+	value.Rules(rules...)(v)
+
+	return v
+}
+
+func newUserRow() *userRow {
+	return &userRow{
+		Name: notSet(rules.MaxLen[string](4)),
+		Age:  notSet(rules.Min(0)),
+	}
+}
+
+func TestReader(t *testing.T) {
+	t.Parallel()
+
+	const file = `name,age,address.city,address.postalCode,unknown
+john,20,Singapore,123456,x
+jessica,-1,,,x
+jane,,Singapore,,x
+,30,,,x
+`
+
+	r := csvx.NewReader[*userRow](strings.NewReader(file))
+	r.New = newUserRow
+
+	var names []string
+	var rows []int
+	err := r.Each(func(row int, u *userRow) error {
+		name, _ := u.Name.Get()
+		rows = append(rows, row)
+		names = append(names, name)
+
+		return nil
+	})
+
+	if len(names) != 1 || names[0] != "john" || rows[0] != 2 {
+		t.Fatalf("expected %v at row 2, got %v at %v", []string{"john"}, names, rows)
+	}
+
+	var report csvx.Report
+	if !errors.As(err, &report) {
+		t.Fatalf("expected %T, got %v", report, err)
+	}
+
+	want := []string{
+		"row 3: age",
+		"row 3: name",
+		"row 4: address.postalCode",
+		"row 4: age",
+		"row 5: name",
+	}
+	if len(report) != len(want) {
+		t.Fatalf("expected %d errors, got %s", len(want), report)
+	}
+
+	for i, e := range report {
+		got := e.Error()
+		if !strings.HasPrefix(got, want[i]+": ") {
+			t.Fatalf("expected %s, got %s", want[i], got)
+		}
+	}
+
+	if !errors.Is(err, value.ErrNotSet) {
+		t.Fatalf("expected %s, got %s", value.ErrNotSet, err)
+	}
+
+	var ruleErr *value.RuleError
+	if !errors.As(report[0], &ruleErr) || ruleErr.Code != "min" {
+		t.Fatalf("expected %s, got %v", "min", report[0])
+	}
+}
+
+func TestReaderRead(t *testing.T) {
+	t.Parallel()
+
+	const file = "name,age,note\njohn,x,\njane,20,hi\n"
+
+	r := csvx.NewReader[userRow](strings.NewReader(file))
+
+	_, err := r.Read()
+
+	var report csvx.Report
+	if !errors.As(err, &report) || len(report) != 1 || report[0].Row != 2 || report[0].Column != "age" {
+		t.Fatalf("expected %s, got %v", "row 2: age", err)
+	}
+
+	u, err := r.Read()
+	if err != nil {
+		t.Fatalf("failed to read: %s", err)
+	}
+
+	if note, _ := u.Note.Get(); note != "hi" {
+		t.Fatalf("expected %s, got %s", "hi", note)
+	}
+
+	if _, err := r.Read(); !errors.Is(err, io.EOF) {
+		t.Fatalf("expected %s, got %v", io.EOF, err)
+	}
+
+	if err := r.Report(); err == nil {
+		t.Fatal("expected report, got nil")
+	}
+}
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	john := newUserRow()
+	if err := john.Name.Set("john"); err != nil {
+		t.Fatalf("failed to set name: %s", err)
+	}
+	if err := john.Age.Set(20); err != nil {
+		t.Fatalf("failed to set age: %s", err)
+	}
+	john.Note.SetNull()
+	john.Address = value.NewObject(&address{
+		City:       value.New("Singapore"),
+		PostalCode: value.New("123456"),
+	})
+
+	jane := &userRow{Name: value.New("jane")}
+	if err := jane.Note.Set("hi"); err != nil {
+		t.Fatalf("failed to set note: %s", err)
+	}
+
+	var sb strings.Builder
+	w := csvx.NewWriter[*userRow](&sb)
+	for _, u := range []*userRow{john, jane} {
+		if err := w.Write(u); err != nil {
+			t.Fatalf("failed to write: %s", err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		t.Fatalf("failed to flush: %s", err)
+	}
+
+	want := `name,age,note,address.city,address.postalCode
+john,20,,Singapore,123456
+jane,,hi,,
+`
+	if got := sb.String(); got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		r := csvx.NewReader[userRow](strings.NewReader(sb.String()))
+
+		u, err := r.Read()
+		if err != nil {
+			t.Fatalf("failed to read: %s", err)
+		}
+
+		if city, _ := u.Address.MustGet().City.Get(); city != "Singapore" {
+			t.Fatalf("expected %s, got %s", "Singapore", city)
+		}
+
+		if !u.Note.IsZero() {
+			t.Fatalf("expected note not set, got %v", u.Note)
+		}
+	})
+}
+
+func TestReaderMultiline(t *testing.T) {
+	t.Parallel()
+
+	const file = "name,age,note\njohn,20,\"first\nsecond\"\njane,x,\nanna,30,\n"
+
+	r := csvx.NewReader[userRow](strings.NewReader(file))
+
+	var rows []int
+	err := r.Each(func(row int, _ userRow) error {
+		rows = append(rows, row)
+		return nil
+	})
+
+	if len(rows) != 2 || rows[0] != 2 || rows[1] != 4 {
+		t.Fatalf("expected %v, got %v", []int{2, 4}, rows)
+	}
+
+	var report csvx.Report
+	if !errors.As(err, &report) || len(report) != 1 || report[0].Row != 3 {
+		t.Fatalf("expected %s, got %v", "row 3: age", err)
+	}
+}
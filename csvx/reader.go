@@ -0,0 +1,300 @@
+// Package csvx reads and writes CSV files of structs with Value and Object
+// fields, one row at a time.
+//
+// The columns are named like form fields: after the form tag, the json tag or
+// the field name, with nested fields separated by dots, e.g. "address.city".
+package csvx
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/alextanhongpin/value"
+	"github.com/alextanhongpin/value/form"
+)
+
+// CellError is the error of a cell, at a row number and the column of a
+// field path. The header is row 1.
+type CellError struct {
+	Row    int
+	Column string
+	Err    error
+}
+
+func (e *CellError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("row %d: %s", e.Row, e.Err)
+	}
+
+	return fmt.Sprintf("row %d: %s: %s", e.Row, e.Column, e.Err)
+}
+
+func (e *CellError) Unwrap() error {
+	return e.Err
+}
+
+// Report lists the invalid cells, ordered by row and column.
+type Report []*CellError
+
+func (r Report) Error() string {
+	msgs := make([]string, len(r))
+	for i, err := range r {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+func (r Report) Unwrap() []error {
+	errs := make([]error, len(r))
+	for i, err := range r {
+		errs[i] = err
+	}
+
+	return errs
+}
+
+func (r Report) Is(target error) bool {
+	return value.Errors(r.Unwrap()).Is(target)
+}
+
+func (r Report) As(target any) bool {
+	return value.Errors(r.Unwrap()).As(target)
+}
+
+type validatable interface {
+	Validate() error
+}
+
+// Reader decodes the rows of a CSV file into T, which must be a struct or a
+// pointer to a struct. The first row is the header.
+//
+//	r := csvx.NewReader[UserRow](file)
+//	err := r.Each(func(row int, u UserRow) error {
+//		return store.Save(u)
+//	})
+//	var report csvx.Report
+//	if errors.As(err, &report) {
+//		// Every invalid cell, e.g. "row 3: address.city: not set".
+//	}
+//
+// Only the invalid cells are kept in memory, so large files can be streamed.
+// Columns that do not match a field are ignored.
+type Reader[T any] struct {
+	// New returns the T that each row is decoded into, e.g. with the rules of
+	// its Values. It defaults to the zero value of T, with pointers allocated.
+	New func() T
+
+	r      *csv.Reader
+	header []string
+	report Report
+	// row counts the records read, since cells may span several lines.
+	row int
+}
+
+func NewReader[T any](r io.Reader) *Reader[T] {
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+
+	return &Reader[T]{r: cr}
+}
+
+// Read decodes the next row. Like form.Decode, empty cells leave the field not
+// set, and the rules of Values are enforced. The row is then validated with
+// value.ValidateStruct, and with the Validate method of T if the fields are
+// valid.
+//
+// An invalid row returns the decoded T with a Report of the row, and the
+// next row can still be read. Read returns io.EOF after the last row.
+func (r *Reader[T]) Read() (T, error) {
+	var t T
+	if err := r.readHeader(); err != nil {
+		return t, err
+	}
+
+	record, err := r.r.Read()
+	if record != nil {
+		r.row++
+	}
+	if err != nil {
+		return t, err
+	}
+
+	row := r.row
+
+	values := make(url.Values, len(record))
+	for i, cell := range record {
+		if i < len(r.header) {
+			values.Add(r.header[i], cell)
+		}
+	}
+
+	if r.New != nil {
+		t = r.New()
+	} else {
+		t = value.Zero[T]()
+	}
+
+	ptr := reflect.ValueOf(&t).Elem()
+	if ptr.Kind() != reflect.Pointer {
+		ptr = ptr.Addr()
+	}
+
+	errs := make(value.ErrorMap)
+	if _, err := form.Decode(values, ptr.Interface()); err != nil {
+		var m value.ErrorMap
+		if !errors.As(err, &m) {
+			return t, err
+		}
+
+		for key, err := range m {
+			errs[key] = err
+		}
+	}
+
+	validate(ptr.Interface(), errs)
+	if len(errs) == 0 {
+		return t, nil
+	}
+
+	report := rowReport(row, errs)
+	r.report = append(r.report, report...)
+
+	return t, report
+}
+
+// Each calls fn with the row number and the decoded T of every valid row.
+// Invalid rows are skipped, and are returned as a Report at the end. Other
+// errors, including those returned by fn, stop the reading.
+func (r *Reader[T]) Each(fn func(row int, t T) error) error {
+	for {
+		t, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var report Report
+		if errors.As(err, &report) {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if err := fn(r.row, t); err != nil {
+			return err
+		}
+	}
+
+	return r.Report()
+}
+
+// Report returns the invalid cells of the rows read so far, or nil if there
+// are none.
+func (r *Reader[T]) Report() error {
+	if len(r.report) == 0 {
+		return nil
+	}
+
+	return r.report
+}
+
+func (r *Reader[T]) readHeader() error {
+	if r.header != nil {
+		return nil
+	}
+
+	header, err := r.r.Read()
+	if err != nil {
+		return err
+	}
+	r.row++
+
+	r.header = make([]string, len(header))
+	for i, name := range header {
+		r.header[i] = strings.TrimSpace(name)
+	}
+
+	return nil
+}
+
+// validate adds the errors of the fields that decoded without errors.
+func validate(v any, errs value.ErrorMap) {
+	err := value.ValidateStruct(v)
+	if err == nil {
+		if vv, ok := v.(validatable); ok {
+			err = vv.Validate()
+		}
+	}
+
+	failed := make(map[string]bool, len(errs))
+	for path := range errs {
+		failed[path] = true
+	}
+
+	collect(err, errs, failed)
+}
+
+// collect adds the errors by field path, like value.ErrorMap does, except for
+// the fields that failed to decode.
+func collect(err error, errs value.ErrorMap, failed map[string]bool) {
+	var fieldErr *value.FieldError
+
+	switch e := err.(type) {
+	case nil:
+	case interface{ Unwrap() []error }:
+		for _, err := range e.Unwrap() {
+			collect(err, errs, failed)
+		}
+	default:
+		path := ""
+		if errors.As(err, &fieldErr) {
+			path = fieldErr.Path
+		}
+
+		if failed[path] {
+			return
+		}
+
+		switch prev := errs[path].(type) {
+		case nil:
+			errs[path] = err
+		case value.Errors:
+			errs[path] = append(prev, err)
+		default:
+			errs[path] = value.Errors{prev, err}
+		}
+	}
+}
+
+func rowReport(row int, errs value.ErrorMap) Report {
+	report := make(Report, 0, len(errs))
+	for path, err := range errs {
+		members, ok := err.(value.Errors)
+		if !ok {
+			members = value.Errors{err}
+		}
+
+		for _, err := range members {
+			if fieldErr, ok := err.(*value.FieldError); ok && fieldErr.Path == path {
+				err = fieldErr.Err
+			}
+
+			report = append(report, &CellError{Row: row, Column: path, Err: err})
+		}
+	}
+
+	// The errors of a column keep their order.
+	sort.SliceStable(report, func(i, j int) bool {
+		return report[i].Column < report[j].Column
+	})
+
+	return report
+}
@@ -0,0 +1,240 @@
+package csvx
+
+import (
+	"encoding"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/alextanhongpin/value"
+	"github.com/alextanhongpin/value/form"
+)
+
+var (
+	containerType     = reflect.TypeOf((*value.Container)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// column is a field of the struct, with its nested fields if it is a struct.
+type column struct {
+	name     string
+	index    int
+	children []column
+}
+
+// Writer encodes T, which must be a struct or a pointer to a struct, into the
+// rows of a CSV file. The header is written before the first row.
+//
+// Every field that can be written as text is a column, and the fields of
+// nested structs and objects are flattened, e.g. "address.city". Fields that
+// are not set or null are written as empty cells, which Reader decodes back
+// into fields that are not set. Slices and maps are skipped.
+type Writer[T any] struct {
+	w           *csv.Writer
+	columns     []column
+	wroteHeader bool
+}
+
+func NewWriter[T any](w io.Writer) *Writer[T] {
+	return &Writer[T]{
+		w:       csv.NewWriter(w),
+		columns: columnsOf(reflect.TypeOf(value.Zero[T]())),
+	}
+}
+
+// Header returns the names of the columns.
+func (w *Writer[T]) Header() []string {
+	var header []string
+	for _, c := range w.columns {
+		header = appendNames(header, c, "")
+	}
+
+	return header
+}
+
+// Write writes t as a row. Like csv.Writer, the rows are buffered until Flush
+// is called.
+func (w *Writer[T]) Write(t T) error {
+	if !w.wroteHeader {
+		if err := w.w.Write(w.Header()); err != nil {
+			return err
+		}
+
+		w.wroteHeader = true
+	}
+
+	rv := reflect.New(reflect.TypeOf(&t).Elem()).Elem()
+	rv.Set(reflect.ValueOf(&t).Elem())
+
+	record, err := appendCells(nil, w.columns, indirect(rv))
+	if err != nil {
+		return err
+	}
+
+	return w.w.Write(record)
+}
+
+// Flush writes the buffered rows and reports any error that occurred.
+func (w *Writer[T]) Flush() error {
+	w.w.Flush()
+
+	return w.w.Error()
+}
+
+// columnsOf returns the columns of the struct type.
+func columnsOf(t reflect.Type) []column {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var columns []column
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		// Like form, the fields of embedded structs are promoted, with an
+		// empty name.
+		name, ok := form.FieldName(f)
+		if !ok || !f.IsExported() {
+			continue
+		}
+
+		ft := elemType(f.Type)
+		switch {
+		case isText(ft):
+			columns = append(columns, column{name: name, index: i})
+		case ft.Kind() == reflect.Struct:
+			if children := columnsOf(ft); len(children) > 0 {
+				columns = append(columns, column{name: name, index: i, children: children})
+			}
+		}
+	}
+
+	return columns
+}
+
+// elemType returns the type of the contained value of containers, and of the
+// pointed to value of pointers.
+func elemType(t reflect.Type) reflect.Type {
+	for {
+		switch {
+		case t.Kind() == reflect.Pointer && t.Implements(containerType):
+			t = reflect.TypeOf(reflect.New(t.Elem()).Interface().(value.Container).Any())
+		case reflect.PointerTo(t).Implements(containerType):
+			t = reflect.TypeOf(reflect.New(t).Interface().(value.Container).Any())
+		case t.Kind() == reflect.Pointer && !t.Implements(textMarshalerType):
+			t = t.Elem()
+		default:
+			return t
+		}
+	}
+}
+
+func isText(t reflect.Type) bool {
+	if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+func appendNames(names []string, c column, prefix string) []string {
+	name := c.name
+	if prefix != "" && name != "" {
+		name = prefix + "." + name
+	} else if name == "" {
+		name = prefix
+	}
+
+	if c.children == nil {
+		return append(names, name)
+	}
+
+	for _, child := range c.children {
+		names = appendNames(names, child, name)
+	}
+
+	return names
+}
+
+// appendCells appends the cells of the columns of the struct v. If v is not
+// valid, the cells are empty.
+func appendCells(record []string, columns []column, v reflect.Value) ([]string, error) {
+	for _, c := range columns {
+		var f reflect.Value
+		if v.IsValid() {
+			f = indirect(v.Field(c.index))
+		}
+
+		if c.children != nil {
+			var err error
+			record, err = appendCells(record, c.children, f)
+			if err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		if !f.IsValid() {
+			record = append(record, "")
+			continue
+		}
+
+		if f.CanAddr() {
+			f = f.Addr()
+		}
+
+		b, err := value.MarshalText(f.Interface())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", c.name, err)
+		}
+
+		record = append(record, string(b))
+	}
+
+	return record, nil
+}
+
+// indirect returns the contained value of containers, and the pointed to
+// value of pointers. The returned value is invalid if the container is not
+// set or null, or if the pointer is nil.
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() {
+		switch {
+		case v.Kind() == reflect.Pointer && v.IsNil():
+			return reflect.Value{}
+		case v.Type().Implements(containerType):
+			c := v.Interface().(value.Container)
+			if n, ok := c.(interface{ IsNull() bool }); c.IsZero() || ok && n.IsNull() {
+				return reflect.Value{}
+			}
+
+			// The contained value is copied, so that its fields are
+			// addressable.
+			a := reflect.ValueOf(c.Any())
+			v = reflect.New(a.Type()).Elem()
+			v.Set(a)
+		case v.CanAddr() && v.Addr().Type().Implements(containerType):
+			v = v.Addr()
+		case v.Kind() == reflect.Pointer && !v.Type().Implements(textMarshalerType):
+			v = v.Elem()
+		default:
+			return v
+		}
+	}
+
+	return v
+}
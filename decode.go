@@ -0,0 +1,107 @@
+package value
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Policy controls how a decoder validates the decoded Value, Object and
+// Nullable fields.
+type Policy int
+
+const (
+	// Deferred leaves the validation to the caller, like json.Unmarshal.
+	Deferred Policy = iota
+	// Eager rejects the input if any field is invalid, or missing unless its
+	// `value` tag says otherwise, like ValidateStruct. The errors are returned
+	// as an ErrorMap keyed by the field path.
+	Eager
+	// Collect accepts the input, and adds the errors that Eager would return
+	// to the report of the decoder, keyed by the index of the document.
+	Collect
+)
+
+// DocumentError holds the errors that the Collect policy found in a decoded
+// document, at its index in the stream, starting from 0.
+type DocumentError struct {
+	Index int
+	Err   error
+}
+
+func (e *DocumentError) Error() string {
+	return fmt.Sprintf("document %d: %s", e.Index, e.Err)
+}
+
+func (e *DocumentError) Unwrap() error {
+	return e.Err
+}
+
+// report holds the errors collected by the Collect policy, per document.
+type report struct {
+	n    int
+	errs Errors
+}
+
+// apply validates v like ValidateStruct, according to the policy. v is
+// the next document decoded from the stream.
+func (r *report) apply(p Policy, v any) error {
+	i := r.n
+	r.n++
+
+	if p == Deferred {
+		return nil
+	}
+
+	err := validateDecoded(v)
+	if err == nil || p == Eager {
+		return err
+	}
+
+	r.errs = append(r.errs, &DocumentError{Index: i, Err: err})
+
+	return nil
+}
+
+func (r *report) err() error {
+	if len(r.errs) == 0 {
+		return nil
+	}
+
+	return r.errs
+}
+
+// JSONDecoder decodes JSON into structs with Value, Object and Nullable
+// fields, and validates them according to the policy.
+//
+//	dec := value.NewJSONDecoder(r.Body)
+//	dec.Policy = value.Eager
+//	if err := dec.Decode(&dto); err != nil {
+//		// err is an ErrorMap, e.g. {"age": "age: invalid age range: -1"}.
+//	}
+type JSONDecoder struct {
+	*json.Decoder
+
+	Policy Policy
+	report report
+}
+
+func NewJSONDecoder(r io.Reader) *JSONDecoder {
+	return &JSONDecoder{
+		Decoder: json.NewDecoder(r),
+	}
+}
+
+func (d *JSONDecoder) Decode(v any) error {
+	if err := d.Decoder.Decode(v); err != nil {
+		return err
+	}
+
+	return d.report.apply(d.Policy, v)
+}
+
+// Report returns the errors collected by the Collect policy as Errors with a
+// *DocumentError for each invalid document, or nil if there are none.
+func (d *JSONDecoder) Report() error {
+	return d.report.err()
+}
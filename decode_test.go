@@ -0,0 +1,105 @@
+package value_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alextanhongpin/value"
+)
+
+func TestJSONDecoder(t *testing.T) {
+	t.Parallel()
+
+	raw := `{"createdBy": "admin", "id": "order-1", "items": [{"sku": "a", "quantity": 1}, {"sku": "", "quantity": 1}]}`
+
+	t.Run("deferred", func(t *testing.T) {
+		t.Parallel()
+
+		var o order
+		if err := value.NewJSONDecoder(strings.NewReader(raw)).Decode(&o); err != nil {
+			t.Fatalf("expected deferred decode, got %s", err)
+		}
+
+		if o.Items[1].SKU.Valid() {
+			t.Fatal("expected invalid sku to be decoded")
+		}
+	})
+
+	t.Run("eager", func(t *testing.T) {
+		t.Parallel()
+
+		dec := value.NewJSONDecoder(strings.NewReader(raw))
+		dec.Policy = value.Eager
+
+		var o order
+		err := dec.Decode(&o)
+
+		var errs value.ErrorMap
+		if !errors.As(err, &errs) || len(errs) != 1 {
+			t.Fatalf("expected 1 error, got %v", err)
+		}
+
+		if !errors.Is(errs["items[1].sku"], value.ErrNotSet) {
+			t.Fatalf("expected %s, got %v", value.ErrNotSet, errs["items[1].sku"])
+		}
+	})
+
+	t.Run("eager required", func(t *testing.T) {
+		t.Parallel()
+
+		// Like ValidateStruct, fields are required unless their tag says
+		// otherwise.
+		dec := value.NewJSONDecoder(strings.NewReader(`{"id": "order-1", "items": [{"sku": "a"}]}`))
+		dec.Policy = value.Eager
+
+		var o order
+		err := dec.Decode(&o)
+
+		var errs value.ErrorMap
+		if !errors.As(err, &errs) || len(errs) != 2 {
+			t.Fatalf("expected 2 errors, got %v", err)
+		}
+
+		for _, path := range []string{"createdBy", "items[0].quantity"} {
+			if !errors.Is(errs[path], value.ErrNotSet) {
+				t.Fatalf("expected %s at %s, got %v", value.ErrNotSet, path, errs)
+			}
+		}
+	})
+
+	t.Run("collect", func(t *testing.T) {
+		t.Parallel()
+
+		stream := raw +
+			`{"createdBy": "admin", "id": "order-2", "items": [{"sku": "b", "quantity": 1}]}` +
+			`{"createdBy": "admin", "id": "order-3", "items": [{"sku": "c", "quantity": 1}, {"sku": "", "quantity": 1}]}`
+
+		dec := value.NewJSONDecoder(strings.NewReader(stream))
+		dec.Policy = value.Collect
+
+		for i := 0; i < 3; i++ {
+			var o order
+			if err := dec.Decode(&o); err != nil {
+				t.Fatalf("expected collect decode, got %s", err)
+			}
+		}
+
+		var report value.Errors
+		if err := dec.Report(); !errors.As(err, &report) || len(report) != 2 {
+			t.Fatalf("expected report of 2 documents, got %v", err)
+		}
+
+		for i, index := range []int{0, 2} {
+			var docErr *value.DocumentError
+			if !errors.As(report[i], &docErr) || docErr.Index != index {
+				t.Fatalf("expected document %d, got %v", index, report[i])
+			}
+
+			var errs value.ErrorMap
+			if !errors.As(docErr.Err, &errs) || len(errs) != 1 || !errors.Is(errs["items[1].sku"], value.ErrNotSet) {
+				t.Fatalf("expected %s at items[1].sku, got %v", value.ErrNotSet, docErr.Err)
+			}
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package value
+
+import (
+	"encoding"
+	"fmt"
+	"strings"
+)
+
+// Enum is a set of allowed values. It implements Rule, so it can be attached
+// to a Value.
+//
+//	var units = value.NewEnum(UnitMM, UnitCM, UnitM)
+//	unit := value.New(UnitMM, value.Rules[Unit](units))
+type Enum[T comparable] struct {
+	values []T
+}
+
+func NewEnum[T comparable](values ...T) *Enum[T] {
+	return &Enum[T]{values: values}
+}
+
+// Values returns a copy of the allowed values, in order.
+func (e *Enum[T]) Values() []T {
+	values := make([]T, len(e.values))
+	copy(values, e.values)
+
+	return values
+}
+
+func (e *Enum[T]) Contains(t T) bool {
+	for _, v := range e.values {
+		if v == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e *Enum[T]) Validate(t T) error {
+	if e.Contains(t) {
+		return nil
+	}
+
+	return e.error(fmt.Sprint(t))
+}
+
+// Parse returns the value whose text form matches s. The text form is the
+// MarshalText output if T implements encoding.TextMarshaler, and the fmt
+// output otherwise.
+func (e *Enum[T]) Parse(s string) (T, error) {
+	for _, v := range e.values {
+		if text(v) == s {
+			return v, nil
+		}
+	}
+
+	var t T
+	return t, e.error(s)
+}
+
+// JSONSchema returns the schema of the enum, with the values in their text
+// form if T implements encoding.TextMarshaler.
+func (e *Enum[T]) JSONSchema() map[string]any {
+	values := make([]any, len(e.values))
+	for i, v := range e.values {
+		if _, ok := any(v).(encoding.TextMarshaler); ok {
+			values[i] = text(v)
+		} else {
+			values[i] = v
+		}
+	}
+
+	return map[string]any{"enum": values}
+}
+
+func (e *Enum[T]) Constraints() []Constraint {
+	return []Constraint{{
+		Code:   CodeOneOf,
+		Params: map[string]any{"values": e.Values()},
+	}}
+}
+
+func (e *Enum[T]) error(s string) error {
+	names := make([]string, len(e.values))
+	for i, v := range e.values {
+		names[i] = text(v)
+	}
+
+	return &RuleError{
+		Code:    CodeOneOf,
+		Params:  map[string]any{"values": e.Values()},
+		Message: fmt.Sprintf("unknown value %q, must be one of %s", s, strings.Join(names, ", ")),
+	}
+}
+
+func text(v any) string {
+	if m, ok := v.(encoding.TextMarshaler); ok {
+		if b, err := m.MarshalText(); err == nil {
+			return string(b)
+		}
+	}
+
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,40 @@
+package value_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alextanhongpin/value"
+)
+
+type unit string
+
+func TestEnum(t *testing.T) {
+	t.Parallel()
+
+	units := value.NewEnum[unit]("mm", "cm", "m")
+
+	if u, err := units.Parse("cm"); err != nil || u != "cm" {
+		t.Fatalf("expected cm, got %s, %v", u, err)
+	}
+
+	_, err := units.Parse("km")
+	if !errors.Is(err, value.ErrInvalidValue) {
+		t.Fatalf("expected %s, got %v", value.ErrInvalidValue, err)
+	}
+
+	if want := "mm, cm, m"; !strings.Contains(err.Error(), want) {
+		t.Fatalf("expected %q to list %s", err, want)
+	}
+
+	length := value.New[unit]("mm", value.Rules[unit](units))
+	if err := length.Set("km"); !errors.Is(err, value.ErrInvalidValue) {
+		t.Fatalf("expected %s, got %v", value.ErrInvalidValue, err)
+	}
+
+	schema := units.JSONSchema()
+	if got := len(schema["enum"].([]any)); got != 3 {
+		t.Fatalf("expected 3 enum values, got %d", got)
+	}
+}
@@ -0,0 +1,45 @@
+package value
+
+import (
+	"errors"
+	"strings"
+)
+
+// Errors is a list of errors that reports every failure at once.
+type Errors []error
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+func (e Errors) Unwrap() []error {
+	return e
+}
+
+// Is reports whether any error matches the target. Together with As, this
+// allows errors.Is and errors.As to match members on Go versions that do not
+// unwrap multiple errors.
+func (e Errors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e Errors) As(target any) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
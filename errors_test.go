@@ -0,0 +1,41 @@
+package value_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alextanhongpin/value"
+	"github.com/alextanhongpin/value/rules"
+)
+
+func TestValidateEach(t *testing.T) {
+	t.Parallel()
+
+	var name *value.Value[string]
+	age := value.New(-1, value.Rules(rules.Min(0)))
+	email := value.New("john@mail.com")
+
+	err := value.ValidateEach(name, age, email)
+
+	var errs value.Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected errors, got %T", err)
+	}
+
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %d", len(errs))
+	}
+
+	if !errors.Is(err, value.ErrNotSet) {
+		t.Fatalf("expected %s, got %s", value.ErrNotSet, err)
+	}
+
+	var ruleErr *value.RuleError
+	if !errors.As(err, &ruleErr) {
+		t.Fatalf("expected rule error, got %s", err)
+	}
+
+	if err := value.ValidateEach(email); err != nil {
+		t.Fatalf("expected nil, got %s", err)
+	}
+}
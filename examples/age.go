@@ -44,14 +44,19 @@ type User struct {
 	Age *value.Object[*Age] `json:"age"`
 }
 
+func (u *User) Validate() error {
+	return value.ValidateStruct(u)
+}
+
 func (u *User) Valid() bool {
-	return u.Age.Valid()
+	return u.Validate() == nil
 }
 
 func (u *User) Errors() map[string]error {
-	return map[string]error{
-		"age": u.Age.Validate(),
-	}
+	var errs value.ErrorMap
+	errors.As(u.Validate(), &errs)
+
+	return errs
 }
 
 var (
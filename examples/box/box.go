@@ -2,7 +2,8 @@ package box
 
 import (
 	"errors"
-	"fmt"
+
+	"github.com/alextanhongpin/value"
 )
 
 var (
@@ -30,27 +31,27 @@ func (b *Box) Validate() error {
 	}
 
 	if b.Length == nil {
-		return fmt.Errorf("%w: box length", ErrDimensionNotSet)
+		return value.WithField("length", ErrDimensionNotSet)
 	}
 
 	if err := b.Length.Validate(); err != nil {
-		return fmt.Errorf("%w: box length", err)
+		return value.WithField("length", err)
 	}
 
 	if b.Width == nil {
-		return fmt.Errorf("%w: box width", ErrDimensionNotSet)
+		return value.WithField("width", ErrDimensionNotSet)
 	}
 
 	if err := b.Width.Validate(); err != nil {
-		return fmt.Errorf("%w: box width", err)
+		return value.WithField("width", err)
 	}
 
 	if b.Height == nil {
-		return fmt.Errorf("%w: box height", ErrDimensionNotSet)
+		return value.WithField("height", ErrDimensionNotSet)
 	}
 
 	if err := b.Height.Validate(); err != nil {
-		return fmt.Errorf("%w: box height", err)
+		return value.WithField("height", err)
 	}
 
 	units := make(map[Unit]int)
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+
+	"github.com/alextanhongpin/value"
 )
 
 var (
@@ -39,15 +41,15 @@ func (rgb *RGB) Validate() error {
 	}
 
 	if err := rgb.validateChannel(rgb.R); err != nil {
-		return fmt.Errorf("%w: R", err)
+		return value.WithField("R", err)
 	}
 
 	if err := rgb.validateChannel(rgb.G); err != nil {
-		return fmt.Errorf("%w: G", err)
+		return value.WithField("G", err)
 	}
 
 	if err := rgb.validateChannel(rgb.B); err != nil {
-		return fmt.Errorf("%w: B", err)
+		return value.WithField("B", err)
 	}
 
 	return nil
@@ -68,6 +70,15 @@ func (r RGB) MarshalJSON() ([]byte, error) {
 	return json.Marshal(r.String())
 }
 
+// JSONSchema describes the string encoding of RGB, since it is not a JSON
+// object.
+func (RGB) JSONSchema() map[string]any {
+	return map[string]any{
+		"type":    "string",
+		"pattern": `^rgb\(\s*\d{1,3},\s*\d{1,3},\s*\d{1,3}\)$`,
+	}
+}
+
 func (r *RGB) UnmarshalJSON(raw []byte) error {
 	if bytes.Equal(raw, []byte("null")) {
 		return nil
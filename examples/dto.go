@@ -98,22 +98,22 @@ func (a *Address) Validate() error {
 		return ErrAddressNotSet
 	}
 	if a.Street1 == "" {
-		return fmt.Errorf("%w: street1", ErrIncompleteAddress)
+		return value.WithField("street1", ErrIncompleteAddress)
 	}
 	if a.Street2 == "" {
-		return fmt.Errorf("%w: street2", ErrIncompleteAddress)
+		return value.WithField("street2", ErrIncompleteAddress)
 	}
 	if a.City == "" {
-		return fmt.Errorf("%w: city", ErrIncompleteAddress)
+		return value.WithField("city", ErrIncompleteAddress)
 	}
 	if a.State == "" {
-		return fmt.Errorf("%w: state", ErrIncompleteAddress)
+		return value.WithField("state", ErrIncompleteAddress)
 	}
 	if a.PostalCode == "" {
-		return fmt.Errorf("%w: postal code", ErrIncompleteAddress)
+		return value.WithField("postalCode", ErrIncompleteAddress)
 	}
 	if a.Country == "" {
-		return fmt.Errorf("%w: country", ErrIncompleteAddress)
+		return value.WithField("country", ErrIncompleteAddress)
 	}
 
 	return nil
@@ -139,7 +139,7 @@ func (dto *CreateUserDto) Validate() error {
 		return ErrDtoNotSet
 	}
 
-	return value.ValidateAll(dto.Name, dto.Email, dto.Address)
+	return value.ValidateStruct(dto)
 }
 
 func (dto *CreateUserDto) Valid() bool {
@@ -147,9 +147,8 @@ func (dto *CreateUserDto) Valid() bool {
 }
 
 func (dto *CreateUserDto) Errors() map[string]error {
-	return map[string]error{
-		"name":    dto.Name.Validate(),
-		"email":   dto.Email.Validate(),
-		"address": dto.Address.Validate(),
-	}
+	var errs value.ErrorMap
+	errors.As(dto.Validate(), &errs)
+
+	return errs
 }
@@ -17,7 +17,7 @@ var (
 type HomePage struct {
 	Width           int                        `json:"width"`
 	Height          int                        `json:"height"`
-	BackgroundColor *value.Object[*colors.RGB] `json:"backgroundColor"`
+	BackgroundColor *value.Object[*colors.RGB] `json:"backgroundColor" value:"optional"`
 }
 
 func (p *HomePage) Validate() error {
@@ -25,7 +25,7 @@ func (p *HomePage) Validate() error {
 		return ErrHomePageNotFound
 	}
 
-	return p.BackgroundColor.ValidateOptional()
+	return value.ValidateStruct(p)
 }
 
 func (p *HomePage) Valid() bool {
@@ -33,9 +33,9 @@ func (p *HomePage) Valid() bool {
 }
 
 type ProfilePage struct {
-	BackgroundColor *colors.RGB
-	HeaderColor     *colors.RGB
-	FooterColor     *colors.RGB
+	BackgroundColor *colors.RGB `json:"backgroundColor"`
+	HeaderColor     *colors.RGB `json:"headerColor"`
+	FooterColor     *colors.RGB `json:"footerColor"`
 }
 
 func (p *ProfilePage) Validate() error {
@@ -43,19 +43,7 @@ func (p *ProfilePage) Validate() error {
 		return ErrProfilePageNotFound
 	}
 
-	if err := p.BackgroundColor.Validate(); err != nil {
-		return fmt.Errorf("%w: backgroundColor", err)
-	}
-
-	if err := p.HeaderColor.Validate(); err != nil {
-		return fmt.Errorf("%w: headerColor", err)
-	}
-
-	if err := p.FooterColor.Validate(); err != nil {
-		return fmt.Errorf("%w: footerColor", err)
-	}
-
-	return nil
+	return value.ValidateStruct(p)
 }
 
 func main() {
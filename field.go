@@ -0,0 +1,123 @@
+package value
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	CodeNotSet  = "not_set"
+	CodeNull    = "null"
+	CodeInvalid = "invalid"
+	CodeOneOf   = "one_of"
+)
+
+// FieldError is an error located at a field path, e.g. "address.postalCode"
+// or "items[3].sku".
+type FieldError struct {
+	Path string
+	Code string
+	Err  error
+}
+
+func (e *FieldError) Error() string {
+	if e.Path == "" {
+		return e.Err.Error()
+	}
+
+	return e.Path + ": " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// WithField prefixes the path of the error with the field name. Errors that
+// are not a FieldError are wrapped in one. Each member of Errors and ErrorMap
+// is prefixed individually.
+func WithField(name string, err error) error {
+	return withPath(name, err)
+}
+
+// WithIndex prefixes the path of the error with the index, e.g. "[3]".
+func WithIndex(i int, err error) error {
+	return withPath("["+strconv.Itoa(i)+"]", err)
+}
+
+// Field names the validatable, so that its errors are prefixed with the field
+// name.
+//
+//	value.ValidateEach(value.Field("name", dto.Name), value.Field("email", dto.Email))
+func Field(name string, v validatable) validatable {
+	return &field{name: name, v: v}
+}
+
+type field struct {
+	name string
+	v    validatable
+}
+
+func (f *field) Validate() error {
+	return WithField(f.name, f.v.Validate())
+}
+
+func withPath(segment string, err error) error {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *FieldError:
+		return &FieldError{
+			Path: joinPath(segment, e.Path),
+			Code: e.Code,
+			Err:  e.Err,
+		}
+	case Errors:
+		errs := make(Errors, len(e))
+		for i, err := range e {
+			errs[i] = withPath(segment, err)
+		}
+
+		return errs
+	case ErrorMap:
+		errs := make(ErrorMap, len(e))
+		for _, err := range e {
+			errs.add(withPath(segment, err))
+		}
+
+		return errs
+	default:
+		return &FieldError{
+			Path: segment,
+			Code: codeOf(err),
+			Err:  err,
+		}
+	}
+}
+
+func joinPath(parent, child string) string {
+	switch {
+	case parent == "":
+		return child
+	case child == "":
+		return parent
+	case strings.HasPrefix(child, "["):
+		return parent + child
+	default:
+		return parent + "." + child
+	}
+}
+
+func codeOf(err error) string {
+	var ruleErr *RuleError
+	switch {
+	case errors.As(err, &ruleErr):
+		return ruleErr.Code
+	case errors.Is(err, ErrNotSet), errors.Is(err, ErrObjectNotSet):
+		return CodeNotSet
+	case errors.Is(err, ErrNull):
+		return CodeNull
+	default:
+		return CodeInvalid
+	}
+}
@@ -0,0 +1,61 @@
+package value_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alextanhongpin/value"
+	"github.com/alextanhongpin/value/rules"
+)
+
+func TestWithField(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nested", func(t *testing.T) {
+		t.Parallel()
+
+		sku := value.New("", value.Rules(rules.NotEmpty[string]()))
+		err := value.WithField("items", value.WithIndex(3, value.WithField("sku", sku.Validate())))
+
+		var fieldErr *value.FieldError
+		if !errors.As(err, &fieldErr) {
+			t.Fatalf("expected field error, got %T", err)
+		}
+
+		if want := "items[3].sku"; fieldErr.Path != want {
+			t.Fatalf("expected %s, got %s", want, fieldErr.Path)
+		}
+
+		if fieldErr.Code != rules.CodeNotEmpty {
+			t.Fatalf("expected %s, got %s", rules.CodeNotEmpty, fieldErr.Code)
+		}
+
+		if !errors.Is(err, value.ErrInvalidValue) {
+			t.Fatalf("expected %s, got %s", value.ErrInvalidValue, err)
+		}
+	})
+
+	t.Run("each", func(t *testing.T) {
+		t.Parallel()
+
+		var name *value.Value[string]
+		var email *value.Value[string]
+		err := value.WithField("user", value.ValidateEach(
+			value.Field("name", name),
+			value.Field("email", email),
+		))
+
+		want := "user.name: not set; user.email: not set"
+		if got := err.Error(); want != got {
+			t.Fatalf("expected %s, got %s", want, got)
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		t.Parallel()
+
+		if err := value.WithField("name", nil); err != nil {
+			t.Fatalf("expected nil, got %s", err)
+		}
+	})
+}
@@ -0,0 +1,137 @@
+// Package flagx binds Value and Object to the flag package.
+//
+//	var port value.Value[int]
+//	fs.Var(flagx.Value(&port).Required(), "port", "the port to listen on")
+//	fs.Parse(os.Args[1:])
+//
+//	if err := flagx.Check(fs); err != nil {
+//		// required flags not set: port
+//	}
+package flagx
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/alextanhongpin/value"
+)
+
+var ErrRequired = errors.New("required flags not set")
+
+type validatable interface {
+	Validate() error
+}
+
+// Flag adapts a Value or Object to flag.Getter. Flags of type bool can be
+// passed without a value, e.g. -verbose.
+type Flag struct {
+	c        value.Container
+	set      func(string) error
+	text     func() ([]byte, error)
+	required bool
+}
+
+// Value adapts the Value to a flag. The text is parsed with the UnmarshalText
+// method of T if it has one, and with strconv otherwise. The rules of the
+// value are enforced.
+func Value[T any](v *value.Value[T]) *Flag {
+	return &Flag{
+		c:    v,
+		set:  func(s string) error { return v.UnmarshalText([]byte(s)) },
+		text: v.MarshalText,
+	}
+}
+
+// Object adapts the Object to a flag. Unlike decoding, the object is
+// validated when the flag is parsed, and is left unchanged if it is invalid.
+func Object[T validatable](o *value.Object[T]) *Flag {
+	return &Flag{
+		c: o,
+		set: func(s string) error {
+			var tmp value.Object[T]
+			if err := tmp.UnmarshalText([]byte(s)); err != nil {
+				return err
+			}
+
+			return o.Set(tmp.MustGet())
+		},
+		text: o.MarshalText,
+	}
+}
+
+// Required marks the flag as required, so that it is listed by Missing if it
+// is not set after parsing.
+func (f *Flag) Required() *Flag {
+	f.required = true
+
+	return f
+}
+
+// IsZero returns true if the flag is not set, either by a default value or by
+// the command line.
+func (f *Flag) IsZero() bool {
+	return f.c == nil || f.c.IsZero()
+}
+
+func (f *Flag) String() string {
+	// The flag package calls String on a zero Flag to print the defaults.
+	if f.IsZero() {
+		return ""
+	}
+
+	b, err := f.text()
+	if err != nil {
+		return ""
+	}
+
+	return string(b)
+}
+
+func (f *Flag) Set(s string) error {
+	return f.set(s)
+}
+
+// Get returns the value of the flag, or nil if it is not set.
+func (f *Flag) Get() any {
+	if f.IsZero() {
+		return nil
+	}
+
+	return f.c.Any()
+}
+
+func (f *Flag) IsBoolFlag() bool {
+	if f.c == nil {
+		return false
+	}
+
+	return reflect.Indirect(reflect.ValueOf(f.c.Any())).Kind() == reflect.Bool
+}
+
+// Missing returns the names of the required flags that are not set, sorted.
+func Missing(fs *flag.FlagSet) []string {
+	var names []string
+	fs.VisitAll(func(fl *flag.Flag) {
+		if f, ok := fl.Value.(*Flag); ok && f.required && f.IsZero() {
+			names = append(names, fl.Name)
+		}
+	})
+	sort.Strings(names)
+
+	return names
+}
+
+// Check returns an error wrapping ErrRequired that lists the required flags
+// that are not set.
+func Check(fs *flag.FlagSet) error {
+	names := Missing(fs)
+	if len(names) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrRequired, strings.Join(names, ", "))
+}
@@ -0,0 +1,112 @@
+package flagx_test
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/alextanhongpin/value"
+	"github.com/alextanhongpin/value/flagx"
+	"github.com/alextanhongpin/value/rules"
+)
+
+type level string
+
+func (l *level) Validate() error {
+	switch *l {
+	case "debug", "info", "error":
+		return nil
+	default:
+		return value.ErrInvalidValue
+	}
+}
+
+type cli struct {
+	fs      *flag.FlagSet
+	port    *value.Value[int]
+	host    *value.Value[string]
+	verbose *value.Value[bool]
+	level   *value.Object[*level]
+}
+
+func newCLI() *cli {
+	c := &cli{
+		fs:      flag.NewFlagSet("test", flag.ContinueOnError),
+		port:    value.New(0, value.Rules(rules.Between(1, 65535))),
+		host:    &value.Value[string]{},
+		verbose: &value.Value[bool]{},
+		level:   &value.Object[*level]{},
+	}
+	c.fs.SetOutput(io.Discard)
+	c.fs.Var(flagx.Value(c.port), "port", "the port")
+	c.fs.Var(flagx.Value(c.host).Required(), "host", "the host")
+	c.fs.Var(flagx.Value(c.verbose), "verbose", "verbose output")
+	c.fs.Var(flagx.Object(c.level).Required(), "level", "the log level")
+
+	return c
+}
+
+func TestFlag(t *testing.T) {
+	t.Parallel()
+
+	t.Run("parse", func(t *testing.T) {
+		t.Parallel()
+
+		c := newCLI()
+		if err := c.fs.Parse([]string{"-port", "80", "-verbose", "-level", "info", "-host", "localhost"}); err != nil {
+			t.Fatalf("failed to parse: %s", err)
+		}
+
+		if err := flagx.Check(c.fs); err != nil {
+			t.Fatalf("expected no missing flags, got %s", err)
+		}
+
+		if c.port.MustGet() != 80 || !c.verbose.MustGet() || *c.level.MustGet() != "info" {
+			t.Fatalf("unexpected flags: %s, %s, %v", c.port, c.verbose, c.level)
+		}
+
+		if got := c.fs.Lookup("port").Value.(flag.Getter).Get(); got != 80 {
+			t.Fatalf("expected 80, got %v", got)
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		t.Parallel()
+
+		c := newCLI()
+		if err := c.fs.Parse(nil); err != nil {
+			t.Fatalf("failed to parse: %s", err)
+		}
+
+		if got := strings.Join(flagx.Missing(c.fs), ","); got != "host,level" {
+			t.Fatalf("expected host,level, got %s", got)
+		}
+
+		if err := flagx.Check(c.fs); !errors.Is(err, flagx.ErrRequired) {
+			t.Fatalf("expected %s, got %v", flagx.ErrRequired, err)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		for _, args := range [][]string{
+			{"-port", "0"},
+			{"-port", "http"},
+			{"-level", "trace"},
+		} {
+			c := newCLI()
+			if err := c.fs.Parse(args); err == nil {
+				t.Fatalf("expected %v to fail", args)
+			}
+		}
+
+		c := newCLI()
+		_ = c.fs.Parse([]string{"-level", "trace"})
+		if !c.level.IsZero() {
+			t.Fatalf("expected invalid level to be not set, got %v", c.level)
+		}
+	})
+}
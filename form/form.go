@@ -0,0 +1,335 @@
+// Package form decodes url.Values and multipart forms into structs with
+// Value, Object and Nullable fields.
+//
+//	type CreateOrderDto struct {
+//		Address *value.Object[*Address] `form:"address"`
+//		Items   []LineItem              `form:"items"`
+//	}
+//
+//	// address.city=Singapore&items[0].sku=A1&items[0].quantity=2
+//	fields, err := form.DecodeRequest(r, &dto)
+//
+// Fields are named after their form tags, falling back to their json tags and
+// then to the field names. Nested fields are separated by dots, and slice
+// elements are addressed by index.
+package form
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alextanhongpin/value"
+)
+
+const (
+	// MaxIndex is the largest slice index accepted, so that a form cannot
+	// allocate arbitrarily large slices.
+	MaxIndex = 1000
+
+	// DefaultMaxMemory is the memory limit used to parse multipart forms.
+	// Larger files are stored on disk.
+	DefaultMaxMemory = 32 << 20
+)
+
+// Fields is the set of paths of the fields that were present in the form,
+// including the fields with empty values.
+type Fields map[string]bool
+
+// Has reports whether the field, or any of its nested fields, was present.
+func (f Fields) Has(path string) bool {
+	if f[path] {
+		return true
+	}
+
+	for p := range f {
+		if strings.HasPrefix(p, path+".") || strings.HasPrefix(p, path+"[") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DecodeRequest parses the form of the request, including multipart forms,
+// and decodes it into v. Like r.Form, the query parameters are included.
+func DecodeRequest(r *http.Request, v any) (Fields, error) {
+	var err error
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		err = r.ParseMultipartForm(DefaultMaxMemory)
+	} else {
+		err = r.ParseForm()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return Decode(r.Form, v)
+}
+
+// Decode decodes the values into v, which must be a pointer to a struct. Only
+// the first value of each key is used, and keys that do not match a field are
+// ignored.
+//
+// Empty values leave the field not set, since browsers submit empty inputs.
+// Use the returned Fields to tell them apart from absent fields.
+//
+// The rules of Values are enforced, while Objects are not validated, like
+// json.Unmarshal. The errors are returned as a value.ErrorMap keyed by the
+// form key.
+func Decode(values url.Values, v any) (Fields, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %T", value.ErrNotStruct, v)
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fields := make(Fields)
+	errs := make(value.ErrorMap)
+	for _, key := range keys {
+		path, ok := parsePath(key)
+		if !ok {
+			continue
+		}
+
+		text := values.Get(key)
+		found, err := walk(rv.Elem(), path, text, text == "")
+		if found {
+			fields[key] = true
+		}
+
+		if err != nil {
+			errs[key] = value.WithField(key, err)
+		}
+	}
+
+	if len(errs) == 0 {
+		return fields, nil
+	}
+
+	return fields, errs
+}
+
+// walk follows the path from v, and decodes the text into the field at the
+// end. When dry, the path is only resolved, without allocating anything.
+func walk(v reflect.Value, path []string, text string, dry bool) (bool, error) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			if dry {
+				return walk(reflect.New(v.Type().Elem()).Elem(), path, text, dry)
+			}
+
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		return walk(v.Elem(), path, text, dry)
+	}
+
+	if c, ok := container(v); ok {
+		elem, err := elemOf(c)
+		if err != nil {
+			return true, err
+		}
+
+		found, err := walk(elem, path, text, dry)
+		if !found || err != nil || dry {
+			return found, err
+		}
+
+		return true, c.SetAny(elem.Interface())
+	}
+
+	if len(path) == 0 {
+		if dry {
+			return true, nil
+		}
+
+		return true, value.ParseText(v, text)
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		f, ok := lookup(v, path[0])
+		if !ok {
+			return false, nil
+		}
+
+		return walk(f, path[1:], text, dry)
+	case reflect.Slice:
+		i, ok := index(path[0])
+		if !ok {
+			return false, nil
+		}
+
+		if i > MaxIndex {
+			return true, fmt.Errorf("%w: index %d exceeds %d", value.ErrInvalidValue, i, MaxIndex)
+		}
+
+		if i >= v.Len() {
+			if dry {
+				return walk(reflect.New(v.Type().Elem()).Elem(), path[1:], text, dry)
+			}
+
+			n := i + 1 - v.Len()
+			v.Set(reflect.AppendSlice(v, reflect.MakeSlice(v.Type(), n, n)))
+		}
+
+		return walk(v.Index(i), path[1:], text, dry)
+	default:
+		return false, nil
+	}
+}
+
+func container(v reflect.Value) (value.Container, bool) {
+	if !v.CanAddr() {
+		return nil, false
+	}
+
+	c, ok := v.Addr().Interface().(value.Container)
+
+	return c, ok
+}
+
+// elemOf returns an addressable copy of the contained value.
+func elemOf(c value.Container) (reflect.Value, error) {
+	a := c.Any()
+	if a == nil {
+		return reflect.Value{}, fmt.Errorf("%w: cannot decode into %T", value.ErrInvalidValue, c)
+	}
+
+	elem := reflect.New(reflect.TypeOf(a)).Elem()
+	elem.Set(reflect.ValueOf(a))
+
+	return elem, nil
+}
+
+// lookup returns the field with the name. Fields of embedded structs are
+// promoted, unless they are shadowed.
+func lookup(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+
+	var embedded []int
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		fname, ok := FieldName(f)
+		if !ok {
+			continue
+		}
+
+		if fname == "" {
+			embedded = append(embedded, i)
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		if fname == name {
+			return v.Field(i), true
+		}
+	}
+
+	for _, i := range embedded {
+		if f, ok := lookup(v.Field(i), name); ok {
+			return f, true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// FieldName returns the name of the field in a form: its form tag, falling
+// back to its json tag and then to its name. Embedded structs without a tag
+// return an empty name, since their fields are promoted. Like encoding/json,
+// fields tagged with "-" are skipped, and return false.
+func FieldName(f reflect.StructField) (string, bool) {
+	name, ok := tagName(f)
+	if !ok {
+		return "", false
+	}
+
+	if name != "" {
+		return name, true
+	}
+
+	if f.Anonymous && f.Type.Kind() == reflect.Struct {
+		return "", true
+	}
+
+	return f.Name, true
+}
+
+// tagName returns the name in the form tag, or else in the json tag. It
+// returns false if the first of them is "-".
+func tagName(f reflect.StructField) (string, bool) {
+	for _, key := range []string{"form", "json"} {
+		if tag, ok := f.Tag.Lookup(key); ok {
+			if tag == "-" {
+				return "", false
+			}
+
+			name, _, _ := strings.Cut(tag, ",")
+			if name != "" {
+				return name, true
+			}
+		}
+	}
+
+	return "", true
+}
+
+// parsePath splits the key into field names and indices, e.g.
+// "items[0].sku" into "items", "[0]" and "sku".
+func parsePath(key string) ([]string, bool) {
+	var path []string
+	for _, part := range strings.Split(key, ".") {
+		name, rest, _ := strings.Cut(part, "[")
+		if name == "" {
+			return nil, false
+		}
+
+		path = append(path, name)
+		for rest != "" {
+			var i string
+			i, rest, _ = strings.Cut(rest, "]")
+			if _, err := strconv.Atoi(i); err != nil {
+				return nil, false
+			}
+
+			path = append(path, "["+i+"]")
+			if rest == "" {
+				break
+			}
+
+			if !strings.HasPrefix(rest, "[") {
+				return nil, false
+			}
+
+			rest = rest[1:]
+		}
+	}
+
+	return path, true
+}
+
+func index(segment string) (int, bool) {
+	if !strings.HasPrefix(segment, "[") {
+		return 0, false
+	}
+
+	i, err := strconv.Atoi(strings.Trim(segment, "[]"))
+
+	return i, err == nil && i >= 0
+}
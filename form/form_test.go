@@ -0,0 +1,196 @@
+package form_test
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/alextanhongpin/value"
+	"github.com/alextanhongpin/value/form"
+	"github.com/alextanhongpin/value/rules"
+)
+
+type address struct {
+	City       *value.Value[string] `form:"city"`
+	PostalCode *value.Value[string] `json:"postalCode"`
+}
+
+func (a *address) Validate() error {
+	return value.ValidateStruct(a)
+}
+
+type sku string
+
+func (s *sku) Validate() error {
+	if s == nil || *s == "" {
+		return value.ErrNotSet
+	}
+
+	return nil
+}
+
+type lineItem struct {
+	SKU      *value.Object[*sku] `form:"sku"`
+	Quantity *value.Value[int]   `form:"quantity"`
+}
+
+type orderForm struct {
+	Name    *value.Value[string]    `form:"name"`
+	Note    value.Nullable[string]  `form:"note"`
+	Address *value.Object[*address] `form:"address"`
+	Items   []lineItem              `form:"items"`
+	Tags    []string                `form:"tags"`
+	Paid    bool                    `form:"paid"`
+	Ignored *value.Value[string]    `form:"-"`
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	values := url.Values{
+		"name":               {"john"},
+		"note":               {""},
+		"address.city":       {"Singapore"},
+		"address.postalCode": {"123456"},
+		"items[1].sku":       {"B2"},
+		"items[0].sku":       {"A1"},
+		"items[0].quantity":  {"2"},
+		"tags[0]":            {"gift"},
+		"paid":               {"true"},
+		"csrf":               {"token"},
+	}
+
+	var dto orderForm
+	fields, err := form.Decode(values, &dto)
+	if err != nil {
+		t.Fatalf("failed to decode: %s", err)
+	}
+
+	if name := dto.Name.MustGet(); name != "john" {
+		t.Fatalf("expected john, got %s", name)
+	}
+
+	if city := dto.Address.MustGet().City.MustGet(); city != "Singapore" {
+		t.Fatalf("expected Singapore, got %s", city)
+	}
+
+	if len(dto.Items) != 2 || *dto.Items[1].SKU.MustGet() != "B2" || dto.Items[0].Quantity.MustGet() != 2 {
+		t.Fatalf("unexpected items: %+v", dto.Items)
+	}
+
+	if !dto.Items[1].Quantity.IsZero() {
+		t.Fatalf("expected quantity to be not set, got %s", dto.Items[1].Quantity)
+	}
+
+	if len(dto.Tags) != 1 || dto.Tags[0] != "gift" || !dto.Paid {
+		t.Fatalf("unexpected plain fields: %+v, %t", dto.Tags, dto.Paid)
+	}
+
+	// Empty values are present, but not set.
+	if !fields.Has("note") || !dto.Note.IsZero() {
+		t.Fatalf("expected note to be present and not set, got %t, %s", fields.Has("note"), dto.Note)
+	}
+
+	if !fields.Has("items") || !fields.Has("address") || fields.Has("csrf") || fields.Has("ignored") {
+		t.Fatalf("unexpected fields: %v", fields)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	t.Parallel()
+
+	dto := orderForm{
+		Name: value.New("", value.Rules(rules.MaxLen[string](4))),
+	}
+
+	values := url.Values{
+		"name":              {"johnny"},
+		"items[0].quantity": {"two"},
+		"items[5000].sku":   {"A1"},
+	}
+
+	_, err := form.Decode(values, &dto)
+
+	var errs value.ErrorMap
+	if !errors.As(err, &errs) || len(errs) != 3 {
+		t.Fatalf("expected 3 errors, got %v", err)
+	}
+
+	if !errors.Is(errs["name"], value.ErrInvalidValue) {
+		t.Fatalf("expected %s, got %v", value.ErrInvalidValue, errs["name"])
+	}
+
+	if errs["items[0].quantity"] == nil || errs["items[5000].sku"] == nil {
+		t.Fatalf("expected items errors, got %v", errs)
+	}
+
+	if _, err := form.Decode(values, dto); !errors.Is(err, value.ErrNotStruct) {
+		t.Fatalf("expected %s, got %v", value.ErrNotStruct, err)
+	}
+}
+
+func TestDecodeRequest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("urlencoded", func(t *testing.T) {
+		t.Parallel()
+
+		r := httptest.NewRequest(http.MethodPost, "/orders?paid=true", bytes.NewBufferString("name=john"))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		var dto orderForm
+		if _, err := form.DecodeRequest(r, &dto); err != nil {
+			t.Fatalf("failed to decode: %s", err)
+		}
+
+		if dto.Name.MustGet() != "john" || !dto.Paid {
+			t.Fatalf("unexpected dto: %+v", dto)
+		}
+	})
+
+	t.Run("multipart", func(t *testing.T) {
+		t.Parallel()
+
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		if err := mw.WriteField("address.city", "Singapore"); err != nil {
+			t.Fatal(err)
+		}
+		mw.Close()
+
+		r := httptest.NewRequest(http.MethodPost, "/orders", &body)
+		r.Header.Set("Content-Type", mw.FormDataContentType())
+
+		var dto orderForm
+		if _, err := form.DecodeRequest(r, &dto); err != nil {
+			t.Fatalf("failed to decode: %s", err)
+		}
+
+		if city := dto.Address.MustGet().City.MustGet(); city != "Singapore" {
+			t.Fatalf("expected Singapore, got %s", city)
+		}
+	})
+}
+
+func TestDecodeSkipped(t *testing.T) {
+	t.Parallel()
+
+	var dto struct {
+		Name   *value.Value[string] `form:"name"`
+		Secret string               `json:"-"`
+		Hidden *value.Value[string] `form:"-"`
+	}
+
+	values := url.Values{"-": {"leak"}, "Secret": {"leak"}, "Hidden": {"leak"}, "name": {"john"}}
+	if _, err := form.Decode(values, &dto); err != nil {
+		t.Fatalf("failed to decode: %s", err)
+	}
+
+	if dto.Secret != "" || dto.Hidden != nil {
+		t.Fatalf("expected skipped fields, got %q and %v", dto.Secret, dto.Hidden)
+	}
+}
@@ -0,0 +1,148 @@
+// Package httpx decodes and validates HTTP request bodies, and renders the
+// failures as RFC 7807 problem details.
+//
+//	func createUser(w http.ResponseWriter, r *http.Request) {
+//		dto, err := httpx.Decode[*CreateUserDto](r)
+//		if err != nil {
+//			httpx.WriteProblem(w, err)
+//			return
+//		}
+//		...
+//	}
+package httpx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/alextanhongpin/value"
+)
+
+// DefaultMaxBytes is the default limit of the request body size.
+const DefaultMaxBytes = 1 << 20
+
+var (
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	ErrBodyTooLarge         = errors.New("request body too large")
+	ErrMalformedBody        = errors.New("malformed request body")
+)
+
+type validatable interface {
+	Validate() error
+}
+
+// Option configures Decode.
+type Option func(*decoder)
+
+// MaxBytes limits the size of the request body. The default is
+// DefaultMaxBytes.
+func MaxBytes(n int64) Option {
+	return func(d *decoder) {
+		d.maxBytes = n
+	}
+}
+
+// AllowUnknownFields accepts fields in the request body that do not exist in
+// the destination. By default they are rejected.
+func AllowUnknownFields() Option {
+	return func(d *decoder) {
+		d.allowUnknownFields = true
+	}
+}
+
+type decoder struct {
+	maxBytes           int64
+	allowUnknownFields bool
+}
+
+// Decode decodes the JSON body of the request into T, and validates it.
+//
+// The request must have a JSON content type, and a body of a single JSON
+// value within the size limit. Unknown fields are rejected.
+//
+// If T is a struct, its fields are validated with value.ValidateStruct, so
+// that every invalid field is reported at once. The Validate method of T is
+// then called for the checks that span multiple fields.
+//
+// The errors wrap ErrUnsupportedMediaType, ErrBodyTooLarge or
+// ErrMalformedBody, or are the validation errors. Use WriteProblem to render
+// them.
+func Decode[T validatable](r *http.Request, opts ...Option) (T, error) {
+	d := &decoder{maxBytes: DefaultMaxBytes}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	t := value.Zero[T]()
+	if err := d.decode(r, &t); err != nil {
+		return t, err
+	}
+
+	return t, validate(t)
+}
+
+func (d *decoder) decode(r *http.Request, v any) error {
+	if err := checkContentType(r.Header.Get("Content-Type")); err != nil {
+		return err
+	}
+
+	if r.Body == nil {
+		return fmt.Errorf("%w: empty body", ErrMalformedBody)
+	}
+
+	// Read one more byte than allowed, to tell whether the limit is exceeded.
+	b, err := io.ReadAll(io.LimitReader(r.Body, d.maxBytes+1))
+	if err != nil {
+		return err
+	}
+
+	if int64(len(b)) > d.maxBytes {
+		return fmt.Errorf("%w: limit is %d bytes", ErrBodyTooLarge, d.maxBytes)
+	}
+
+	if len(bytes.TrimSpace(b)) == 0 {
+		return fmt.Errorf("%w: empty body", ErrMalformedBody)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	if !d.allowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("%w: %s", ErrMalformedBody, err)
+	}
+
+	if dec.More() {
+		return fmt.Errorf("%w: unexpected data after the JSON value", ErrMalformedBody)
+	}
+
+	return nil
+}
+
+func checkContentType(contentType string) error {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrUnsupportedMediaType, contentType)
+	}
+
+	if mediaType != "application/json" && !(strings.HasPrefix(mediaType, "application/") && strings.HasSuffix(mediaType, "+json")) {
+		return fmt.Errorf("%w: %q", ErrUnsupportedMediaType, mediaType)
+	}
+
+	return nil
+}
+
+func validate(t validatable) error {
+	if err := value.ValidateStruct(t); !errors.Is(err, value.ErrNotStruct) && err != nil {
+		return err
+	}
+
+	return t.Validate()
+}
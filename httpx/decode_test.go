@@ -0,0 +1,109 @@
+package httpx_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alextanhongpin/value"
+	"github.com/alextanhongpin/value/httpx"
+	"github.com/alextanhongpin/value/rules"
+)
+
+type signupDto struct {
+	Name     *value.Value[string] `json:"name"`
+	Age      *value.Value[int]    `json:"age"`
+	Password string               `json:"password"`
+	Confirm  string               `json:"confirm"`
+}
+
+func (dto *signupDto) Validate() error {
+	if err := rules.Between(0, 150).Validate(dto.Age.MustGet()); err != nil {
+		return value.WithField("age", err)
+	}
+
+	if dto.Password != dto.Confirm {
+		return value.WithField("confirm", value.ErrInvalidValue)
+	}
+
+	return nil
+}
+
+func handler(w http.ResponseWriter, r *http.Request) {
+	dto, err := httpx.Decode[*signupDto](r, httpx.MaxBytes(128))
+	if err != nil {
+		httpx.WriteProblem(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	_, _ = w.Write([]byte(dto.Name.MustGet()))
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+		params      []string
+	}{
+		{"valid", "application/json", `{"name": "john", "age": 10, "password": "x", "confirm": "x"}`, http.StatusCreated, nil},
+		{"charset", "application/json; charset=utf-8", `{"name": "john", "age": 10}`, http.StatusCreated, nil},
+		{"media type", "text/plain", `{}`, http.StatusUnsupportedMediaType, nil},
+		{"too large", "application/json", `{"name": "` + strings.Repeat("a", 128) + `"}`, http.StatusRequestEntityTooLarge, nil},
+		{"empty", "application/json", ``, http.StatusBadRequest, nil},
+		{"syntax", "application/json", `{"name":`, http.StatusBadRequest, nil},
+		{"unknown field", "application/json", `{"name": "john", "age": 10, "role": "admin"}`, http.StatusBadRequest, nil},
+		{"trailing data", "application/json", `{"name": "john", "age": 10} {}`, http.StatusBadRequest, nil},
+		{"missing fields", "application/json", `{}`, http.StatusUnprocessableEntity, []string{"age", "name"}},
+		{"cross field", "application/json", `{"name": "john", "age": 10, "password": "x"}`, http.StatusUnprocessableEntity, []string{"confirm"}},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(tc.body))
+			r.Header.Set("Content-Type", tc.contentType)
+			w := httptest.NewRecorder()
+			handler(w, r)
+
+			if w.Code != tc.status {
+				t.Fatalf("expected %d, got %d: %s", tc.status, w.Code, w.Body)
+			}
+
+			if w.Code == http.StatusCreated {
+				return
+			}
+
+			if ct := w.Header().Get("Content-Type"); ct != httpx.ContentTypeProblem {
+				t.Fatalf("expected %s, got %s", httpx.ContentTypeProblem, ct)
+			}
+
+			var p httpx.Problem
+			if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+				t.Fatalf("failed to unmarshal problem: %s", err)
+			}
+
+			if p.Status != tc.status || p.Title != http.StatusText(tc.status) {
+				t.Fatalf("unexpected problem: %+v", p)
+			}
+
+			var names []string
+			for _, param := range p.InvalidParams {
+				names = append(names, param.Name)
+			}
+
+			if got, want := strings.Join(names, ","), strings.Join(tc.params, ","); want != got {
+				t.Fatalf("expected invalid params %s, got %s", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package httpx
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/alextanhongpin/value"
+)
+
+// ContentTypeProblem is the media type of RFC 7807 problem details.
+const ContentTypeProblem = "application/problem+json"
+
+// Problem is an RFC 7807 problem details object.
+type Problem struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	Instance      string         `json:"instance,omitempty"`
+	InvalidParams []InvalidParam `json:"invalid-params,omitempty"`
+}
+
+func (p *Problem) Error() string {
+	if p.Detail == "" {
+		return p.Title
+	}
+
+	return p.Title + ": " + p.Detail
+}
+
+// InvalidParam describes an invalid field of the request body. Type is set
+// when the error of the field is registered with a type URI.
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+	Type   string `json:"type,omitempty"`
+}
+
+// NewProblem maps the error to a problem with the DefaultRegistry.
+func NewProblem(err error) *Problem {
+	return DefaultRegistry.Problem(err)
+}
+
+// WriteProblem writes the error as a problem+json response with the
+// DefaultRegistry.
+func WriteProblem(w http.ResponseWriter, err error) {
+	DefaultRegistry.WriteProblem(w, err)
+}
+
+// Register assigns the problem type to the errors matching target in the
+// DefaultRegistry.
+func Register(target error, typ ProblemType) {
+	DefaultRegistry.Register(target, typ)
+}
+
+func writeProblem(w http.ResponseWriter, p *Problem) {
+	w.Header().Set("Content-Type", ContentTypeProblem)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// members returns the errors of Errors and ErrorMap, with nested aggregates
+// flattened. Other errors are returned as the only member.
+func members(err error) []error {
+	var errs []error
+	switch e := err.(type) {
+	case value.ErrorMap:
+		errs = e.Unwrap()
+	case value.Errors:
+		errs = e
+	default:
+		return []error{err}
+	}
+
+	var flat []error
+	for _, err := range errs {
+		flat = append(flat, members(err)...)
+	}
+
+	return flat
+}
@@ -0,0 +1,28 @@
+package httpx_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/alextanhongpin/value"
+	"github.com/alextanhongpin/value/httpx"
+	"github.com/alextanhongpin/value/rules"
+)
+
+func TestNewProblem(t *testing.T) {
+	t.Parallel()
+
+	p := httpx.NewProblem(value.WithField("age", rules.Min(0).Validate(-1)))
+	if p.Status != http.StatusUnprocessableEntity || len(p.InvalidParams) != 1 {
+		t.Fatalf("unexpected problem: %+v", p)
+	}
+
+	if param := p.InvalidParams[0]; param.Code != rules.CodeMin {
+		t.Fatalf("expected %s, got %s", rules.CodeMin, param.Code)
+	}
+
+	if p := httpx.NewProblem(errors.New("db down")); p.Status != http.StatusInternalServerError || p.Detail != "" {
+		t.Fatalf("expected internal errors to be hidden, got %+v", p)
+	}
+}
@@ -0,0 +1,182 @@
+package httpx
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/alextanhongpin/value"
+)
+
+// ProblemType describes the problem of an error.
+type ProblemType struct {
+	// Type is the URI that identifies the problem type. It defaults to
+	// "about:blank".
+	Type string
+	// Title is the short summary of the problem type. It defaults to the
+	// status text.
+	Title  string
+	Status int
+}
+
+var (
+	validationProblem = ProblemType{Status: http.StatusUnprocessableEntity}
+	internalProblem   = ProblemType{Status: http.StatusInternalServerError}
+)
+
+// DefaultRegistry is the registry used by NewProblem and WriteProblem. It
+// maps the errors of Decode, and the validation errors of the value package.
+var DefaultRegistry = NewRegistry()
+
+// Registry maps errors to problem types.
+//
+//	httpx.Register(ErrInvalidAgeRange, httpx.ProblemType{
+//		Type:   "https://example.com/problems/invalid-age-range",
+//		Title:  "Age is out of range",
+//		Status: http.StatusUnprocessableEntity,
+//	})
+//
+// Errors are matched with errors.Is, and the latest registration wins, so
+// that a specific sentinel can override a generic one it wraps.
+//
+// Errors that are not registered map to 422 Unprocessable Entity if they are
+// validation errors of the value package, and to 500 Internal Server Error
+// without details otherwise.
+type Registry struct {
+	mu      sync.RWMutex
+	entries []entry
+}
+
+type entry struct {
+	target error
+	typ    ProblemType
+}
+
+// NewRegistry returns a registry with the errors of Decode and the value
+// package registered:
+//
+//   - ErrUnsupportedMediaType: 415 Unsupported Media Type
+//   - ErrBodyTooLarge: 413 Request Entity Too Large
+//   - ErrMalformedBody: 400 Bad Request
+//   - value.ErrInvalidValue, value.ErrNotSet, value.ErrObjectNotSet and
+//     value.ErrNull: 422 Unprocessable Entity
+func NewRegistry() *Registry {
+	r := &Registry{}
+	r.Register(ErrUnsupportedMediaType, ProblemType{Status: http.StatusUnsupportedMediaType})
+	r.Register(ErrBodyTooLarge, ProblemType{Status: http.StatusRequestEntityTooLarge})
+	r.Register(ErrMalformedBody, ProblemType{Status: http.StatusBadRequest})
+	for _, err := range []error{value.ErrInvalidValue, value.ErrNotSet, value.ErrObjectNotSet, value.ErrNull} {
+		r.Register(err, validationProblem)
+	}
+
+	return r
+}
+
+// Register assigns the problem type to the errors matching target.
+func (r *Registry) Register(target error, typ ProblemType) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = append(r.entries, entry{target: target, typ: typ})
+}
+
+// Problem maps the error to a problem. A *Problem is returned as is.
+//
+// The field errors of Errors and ErrorMap are listed as invalid params. The
+// problem takes the type of the field errors if they share one, and is a
+// generic 422 Unprocessable Entity otherwise.
+func (r *Registry) Problem(err error) *Problem {
+	var problem *Problem
+	if errors.As(err, &problem) {
+		return problem
+	}
+
+	var (
+		typ    ProblemType
+		params []InvalidParam
+	)
+	for i, err := range members(err) {
+		t := r.typeOf(err)
+		if t == internalProblem {
+			// Unknown errors may leak internals, so they are not detailed.
+			return newProblem(t, "")
+		}
+
+		switch {
+		case i == 0:
+			typ = t
+		case t != typ:
+			typ = validationProblem
+		}
+
+		var fieldErr *value.FieldError
+		if !errors.As(err, &fieldErr) || fieldErr.Path == "" {
+			continue
+		}
+
+		params = append(params, InvalidParam{
+			Name:   fieldErr.Path,
+			Code:   fieldErr.Code,
+			Reason: fieldErr.Err.Error(),
+			Type:   t.Type,
+		})
+	}
+
+	p := newProblem(typ, "")
+	p.InvalidParams = params
+	if len(params) == 0 {
+		p.Detail = err.Error()
+	}
+
+	return p
+}
+
+// WriteProblem writes the error as a problem+json response.
+func (r *Registry) WriteProblem(w http.ResponseWriter, err error) {
+	writeProblem(w, r.Problem(err))
+}
+
+func (r *Registry) typeOf(err error) ProblemType {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		if e := r.entries[i]; errors.Is(err, e.target) {
+			return e.typ
+		}
+	}
+
+	if isValidationError(err) {
+		return validationProblem
+	}
+
+	return internalProblem
+}
+
+func newProblem(typ ProblemType, detail string) *Problem {
+	p := &Problem{
+		Type:   typ.Type,
+		Title:  typ.Title,
+		Status: typ.Status,
+		Detail: detail,
+	}
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+
+	return p
+}
+
+func isValidationError(err error) bool {
+	var fieldErr *value.FieldError
+
+	return errors.As(err, &fieldErr) ||
+		errors.Is(err, value.ErrInvalidValue) ||
+		errors.Is(err, value.ErrNotSet) ||
+		errors.Is(err, value.ErrObjectNotSet) ||
+		errors.Is(err, value.ErrNull)
+}
@@ -0,0 +1,75 @@
+package httpx_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alextanhongpin/value"
+	"github.com/alextanhongpin/value/httpx"
+)
+
+var errInvalidAgeRange = errors.New("invalid age range")
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	ageRange := httpx.ProblemType{
+		Type:   "https://example.com/problems/invalid-age-range",
+		Title:  "Age is out of range",
+		Status: http.StatusUnprocessableEntity,
+	}
+
+	r := httpx.NewRegistry()
+	r.Register(errInvalidAgeRange, ageRange)
+
+	t.Run("sentinel", func(t *testing.T) {
+		t.Parallel()
+
+		p := r.Problem(fmt.Errorf("%w: -1", errInvalidAgeRange))
+		if p.Type != ageRange.Type || p.Title != ageRange.Title || p.Detail != "invalid age range: -1" {
+			t.Fatalf("unexpected problem: %+v", p)
+		}
+	})
+
+	t.Run("unregistered", func(t *testing.T) {
+		t.Parallel()
+
+		if p := httpx.NewProblem(errInvalidAgeRange); p.Status != http.StatusInternalServerError || p.Detail != "" {
+			t.Fatalf("expected internal error, got %+v", p)
+		}
+	})
+
+	t.Run("field errors", func(t *testing.T) {
+		t.Parallel()
+
+		err := value.Errors{
+			value.WithField("age", value.ErrNotSet),
+			value.WithField("minAge", errInvalidAgeRange),
+		}
+
+		w := httptest.NewRecorder()
+		r.WriteProblem(w, err)
+
+		var p httpx.Problem
+		if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+			t.Fatalf("failed to unmarshal problem: %s", err)
+		}
+
+		// The fields have different types, so the problem is generic.
+		if p.Type != "about:blank" || p.Status != http.StatusUnprocessableEntity || len(p.InvalidParams) != 2 {
+			t.Fatalf("unexpected problem: %+v", p)
+		}
+
+		if param := p.InvalidParams[0]; param.Name != "age" || param.Code != value.CodeNotSet || param.Type != "" {
+			t.Fatalf("unexpected param: %+v", param)
+		}
+
+		if param := p.InvalidParams[1]; param.Name != "minAge" || param.Type != ageRange.Type {
+			t.Fatalf("unexpected param: %+v", param)
+		}
+	})
+}
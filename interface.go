@@ -37,3 +37,20 @@ func ValidateAll(val ...validatable) error {
 
 	return nil
 }
+
+// ValidateEach is like ValidateAll, but runs every validator and returns all
+// the failures as Errors.
+func ValidateEach(val ...validatable) error {
+	var errs Errors
+	for _, v := range val {
+		if err := v.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
@@ -0,0 +1,647 @@
+// Package jsonschema generates JSON Schema Draft 2020-12 documents from
+// structs with Value, Object and Nullable fields.
+//
+//	dto := CreateUserDto{
+//		Name: value.New("", value.Rules(rules.MaxLen[string](100))),
+//	}
+//	schema, err := jsonschema.Generate(&dto)
+//
+// Since rules are attached to Value instances, the schema is generated from a
+// value rather than a type, and the rules of the non-nil fields are emitted as
+// constraints.
+package jsonschema
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/alextanhongpin/value"
+	"github.com/alextanhongpin/value/rules"
+)
+
+// Draft is the URI of the JSON Schema dialect.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+var ErrInvalidType = errors.New("jsonschema: invalid type")
+
+// Schemer is implemented by types that provide their own schema, like the
+// enums generated by valuegen, or value objects with a custom JSON encoding.
+type Schemer interface {
+	JSONSchema() map[string]any
+}
+
+var (
+	schemerType       = reflect.TypeOf((*Schemer)(nil)).Elem()
+	containerType     = reflect.TypeOf((*value.Container)(nil)).Elem()
+	validatableType   = reflect.TypeOf((*interface{ Validate() error })(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	timeType          = reflect.TypeOf(time.Time{})
+)
+
+// Generate returns the schema of v.
+//
+// Struct fields are named after their json tags. Value, Object and Nullable
+// fields, and other validatable fields, are required unless their `value` tag
+// is optional or omitempty, like value.ValidateStruct. Optional Value and
+// Object fields, and every Nullable field, accept null. Named structs are
+// placed in $defs.
+//
+// The rules attached to Value fields are emitted as constraints, e.g.
+// rules.Min as minimum and rules.Regex as pattern. Types that implement
+// Schemer provide their own schema.
+func Generate(v any) (map[string]any, error) {
+	g := NewGenerator()
+
+	s, err := g.Schema(v)
+	if err != nil {
+		return nil, err
+	}
+
+	s["$schema"] = Draft
+	if defs := g.Defs(); len(defs) > 0 {
+		s["$defs"] = defs
+	}
+
+	return s, nil
+}
+
+// Generator generates schemas that share their definitions, e.g. for the
+// components of an OpenAPI document.
+type Generator struct {
+	// RefPrefix prefixes the references to the definitions. It defaults to
+	// "#/$defs/".
+	RefPrefix string
+
+	defs     map[string]any
+	names    map[reflect.Type]string
+	defined  map[reflect.Type][]string
+	pending  map[string]reflect.Value
+	reserved map[string]bool
+	building map[reflect.Type]bool
+	roots    map[string]bool
+}
+
+func NewGenerator() *Generator {
+	return &Generator{
+		RefPrefix: "#/$defs/",
+		defs:      make(map[string]any),
+		names:     make(map[reflect.Type]string),
+		defined:   make(map[reflect.Type][]string),
+		pending:   make(map[string]reflect.Value),
+		reserved:  make(map[string]bool),
+		building:  make(map[reflect.Type]bool),
+		roots:     make(map[string]bool),
+	}
+}
+
+// Reserve reserves the names for schemas that are kept next to the
+// definitions, e.g. the registered components of an OpenAPI document. Named
+// structs with a reserved name are numbered instead, like types with the same
+// name in different packages.
+func (g *Generator) Reserve(names ...string) {
+	for _, name := range names {
+		g.reserved[name] = true
+	}
+}
+
+// Define adds the schema of v to the definitions under the name, e.g. a
+// registered component of an OpenAPI document. The nested structs of the same
+// type refer to the definition, unless their fields carry other rules. The
+// schema is generated when the definitions are listed, so that the structs
+// defined later are referred to as well.
+func (g *Generator) Define(name string, v any) error {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return fmt.Errorf("%w: %T", ErrInvalidType, v)
+	}
+
+	rv = indirect(rv)
+	g.reserved[name] = true
+	g.pending[name] = rv
+	g.roots[name] = true
+
+	t := rv.Type()
+	if rv.Kind() != reflect.Struct || t.Name() == "" || implements(t, schemerType) {
+		return nil
+	}
+
+	// A zero value is the definition of the type itself.
+	if _, ok := g.names[t]; !ok && rv.IsZero() {
+		g.names[t] = name
+	} else {
+		g.defined[t] = append(g.defined[t], name)
+	}
+
+	return nil
+}
+
+// Schema returns the schema of v, like Generate, but without $schema and
+// $defs. The named structs it refers to are added to the definitions.
+func (g *Generator) Schema(v any) (map[string]any, error) {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return nil, fmt.Errorf("%w: %T", ErrInvalidType, v)
+	}
+
+	rv = indirect(rv)
+	if rv.Kind() == reflect.Struct && !implements(rv.Type(), schemerType) {
+		return g.structSchema(rv), nil
+	}
+
+	return g.schemaOf(rv), nil
+}
+
+// Defs returns the definitions of the named structs referenced by the schemas,
+// keyed by the type name. Types with the same name in different packages are
+// numbered.
+func (g *Generator) Defs() map[string]any {
+	defs := make(map[string]any)
+	var visit func(s any)
+	visit = func(s any) {
+		switch s := s.(type) {
+		case map[string]any:
+			if ref, ok := s["$ref"].(string); ok {
+				name := strings.TrimPrefix(ref, g.RefPrefix)
+				if def := g.def(name); def != nil && defs[name] == nil {
+					defs[name] = def
+					visit(def)
+				}
+			}
+			for _, v := range s {
+				visit(v)
+			}
+		case []any:
+			for _, v := range s {
+				visit(v)
+			}
+		}
+	}
+	for name := range g.roots {
+		visit(map[string]any{"$ref": g.RefPrefix + name})
+	}
+
+	return defs
+}
+
+func (g *Generator) schemaOf(v reflect.Value) map[string]any {
+	v = indirect(v)
+	if !v.IsValid() {
+		return map[string]any{}
+	}
+
+	t := v.Type()
+	switch {
+	case implements(t, schemerType):
+		return copySchema(addressable(v).Interface().(Schemer).JSONSchema())
+	case implements(t, containerType):
+		c := addressable(v).Interface().(value.Container)
+		s := g.schemaOf(reflect.ValueOf(c.Any()))
+		if cs, ok := c.(value.Constrainer); ok {
+			constrain(s, cs.Constraints())
+		}
+
+		return s
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case implements(t, textMarshalerType):
+		return map[string]any{"type": "string"}
+	case implements(t, jsonMarshalerType):
+		// The encoding is unknown, so any value is accepted.
+		return map[string]any{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}
+		}
+
+		var elem reflect.Value
+		if v.Len() > 0 {
+			elem = v.Index(0)
+		} else {
+			elem = reflect.New(t.Elem()).Elem()
+		}
+
+		return map[string]any{"type": "array", "items": g.schemaOf(elem)}
+	case reflect.Map:
+		return map[string]any{
+			"type":                 "object",
+			"additionalProperties": g.schemaOf(reflect.New(t.Elem()).Elem()),
+		}
+	case reflect.Struct:
+		return g.ref(v)
+	default:
+		return map[string]any{}
+	}
+}
+
+// ref places named structs in $defs, and returns a reference to them.
+// Anonymous structs are inlined. The definition is generated from the zero
+// value of the type, so structs whose fields carry rules of their own are
+// inlined too, rather than sharing the rules of another instance. Zero values
+// of the types passed to Define refer to the defined schema instead.
+func (g *Generator) ref(v reflect.Value) map[string]any {
+	t := v.Type()
+	if t.Name() == "" {
+		return g.structSchema(v)
+	}
+
+	defined := g.defined[t]
+	if v.IsZero() && len(defined) > 0 {
+		return g.refTo(defined[0])
+	}
+
+	name := g.define(t)
+	if v.IsZero() {
+		return g.refTo(name)
+	}
+
+	s := g.structSchema(v)
+	for _, n := range defined {
+		if reflect.DeepEqual(s, g.def(n)) {
+			return g.refTo(n)
+		}
+	}
+
+	if reflect.DeepEqual(s, g.def(name)) {
+		return g.refTo(name)
+	}
+
+	return s
+}
+
+func (g *Generator) refTo(name string) map[string]any {
+	// The definitions only list the types referenced outside of other
+	// definitions, and the types those refer to.
+	if len(g.building) == 0 {
+		g.roots[name] = true
+	}
+
+	return map[string]any{"$ref": g.RefPrefix + name}
+}
+
+// def returns the definition with the name, generating it if it was passed to
+// Define.
+func (g *Generator) def(name string) any {
+	v, ok := g.pending[name]
+	if !ok {
+		return g.defs[name]
+	}
+
+	delete(g.pending, name)
+	g.defs[name] = map[string]any{}
+
+	t := v.Type()
+	if v.Kind() != reflect.Struct || implements(t, schemerType) {
+		g.defs[name] = g.schemaOf(v)
+		return g.defs[name]
+	}
+
+	// A zero value is generated like the definitions of nested structs, so
+	// that recursive types refer to themselves.
+	if g.names[t] == name {
+		g.building[t] = true
+		defer delete(g.building, t)
+	}
+
+	g.defs[name] = g.structSchema(v)
+	return g.defs[name]
+}
+
+// define generates the definition of the named struct from its zero value,
+// once, and returns its name.
+func (g *Generator) define(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		g.def(name)
+		return name
+	}
+
+	name := t.Name()
+	for i := 2; g.defs[name] != nil || g.reserved[name]; i++ {
+		name = fmt.Sprintf("%s%d", t.Name(), i)
+	}
+
+	// The name is reserved before the fields are walked, so that recursive
+	// types refer to themselves.
+	g.names[t] = name
+	g.defs[name] = map[string]any{}
+
+	g.building[t] = true
+	g.defs[name] = g.structSchema(reflect.New(t).Elem())
+	delete(g.building, t)
+
+	return name
+}
+
+func (g *Generator) structSchema(v reflect.Value) map[string]any {
+	props := make(map[string]any)
+	var required []string
+	g.fields(v, props, &required)
+
+	s := map[string]any{
+		"type":       "object",
+		"properties": props,
+	}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+
+	return s
+}
+
+func (g *Generator) fields(v reflect.Value, props map[string]any, required *[]string) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct && !implements(f.Type, validatableType) {
+			if fv := indirect(v.Field(i)); fv.IsValid() {
+				g.fields(fv, props, required)
+			} else {
+				g.fields(reflect.New(ft).Elem(), props, required)
+			}
+
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		s := g.schemaOf(v.Field(i))
+
+		var presence string
+		if p := f.Tag.Get("value"); strings.Contains(p, "optional") {
+			presence = "optional"
+		} else if strings.Contains(p, "omitempty") {
+			presence = "omitempty"
+		}
+
+		switch {
+		case implements(f.Type, validatableType):
+			if presence == "" {
+				*required = append(*required, name)
+			}
+
+			// Nullable accepts null, and optional containers encode not set as
+			// null.
+			if isNullable(f.Type) || (presence != "" && implements(f.Type, containerType)) {
+				s = nullable(s)
+			}
+		case f.Type.Kind() == reflect.Pointer:
+			s = nullable(s)
+		}
+
+		props[name] = s
+	}
+}
+
+// nullable allows null in addition to the schema.
+func nullable(s map[string]any) map[string]any {
+	if enum, ok := s["enum"].([]any); ok {
+		s["enum"] = append(append([]any{}, enum...), nil)
+	}
+
+	switch typ := s["type"].(type) {
+	case string:
+		s["type"] = []any{typ, "null"}
+		return s
+	case nil:
+		if len(s) == 0 {
+			return s
+		}
+
+		if _, ok := s["enum"]; ok {
+			return s
+		}
+
+		return map[string]any{"anyOf": []any{s, map[string]any{"type": "null"}}}
+	default:
+		return s
+	}
+}
+
+// CodesKeyword is the extension keyword that maps the validation keywords to
+// the codes of the rules that set them, e.g. {"minimum": "between"}, when they
+// differ from the code of the rule named after the keyword. Clients can then
+// report the same codes as the rules package.
+const CodesKeyword = "x-codes"
+
+// keywordCodes are the codes of the rules named after the keywords.
+var keywordCodes = map[string]string{
+	"minimum":       rules.CodeMin,
+	"maximum":       rules.CodeMax,
+	"minLength":     rules.CodeMinLen,
+	"maxLength":     rules.CodeMaxLen,
+	"minItems":      rules.CodeMinLen,
+	"maxItems":      rules.CodeMaxLen,
+	"minProperties": rules.CodeMinLen,
+	"maxProperties": rules.CodeMaxLen,
+	"pattern":       rules.CodeRegex,
+	"enum":          value.CodeOneOf,
+}
+
+// Code returns the code of the rule that set the keyword of the schema, e.g.
+// "between" for the minimum set by rules.Between.
+func Code(s map[string]any, keyword string) string {
+	if codes, ok := s[CodesKeyword].(map[string]any); ok {
+		if code, ok := codes[keyword].(string); ok {
+			return code
+		}
+	}
+
+	return keywordCodes[keyword]
+}
+
+// constrain adds the constraints of the rules to the schema. Constraints
+// that have no JSON Schema equivalent are skipped.
+func constrain(s map[string]any, cs []value.Constraint) {
+	typ, _ := s["type"].(string)
+	lengths := map[string][2]string{
+		"string": {"minLength", "maxLength"},
+		"array":  {"minItems", "maxItems"},
+		"object": {"minProperties", "maxProperties"},
+	}[typ]
+
+	codes := make(map[string]any)
+	set := func(key string, v any, code string) {
+		s[key] = v
+		if code != keywordCodes[key] {
+			codes[key] = code
+		} else {
+			delete(codes, key)
+		}
+	}
+
+	setLen := func(i int, n any, code string) {
+		if lengths[i] != "" {
+			set(lengths[i], n, code)
+		}
+	}
+
+	setNumber := func(key string, n any, code string) {
+		if isNumber(n) {
+			set(key, n, code)
+		}
+	}
+
+	for _, c := range cs {
+		switch c.Code {
+		case rules.CodeMin:
+			setNumber("minimum", c.Params["min"], c.Code)
+		case rules.CodeMax:
+			setNumber("maximum", c.Params["max"], c.Code)
+		case rules.CodeBetween:
+			setNumber("minimum", c.Params["min"], c.Code)
+			setNumber("maximum", c.Params["max"], c.Code)
+		case rules.CodeLen:
+			setLen(0, c.Params["len"], c.Code)
+			setLen(1, c.Params["len"], c.Code)
+		case rules.CodeMinLen:
+			setLen(0, c.Params["min"], c.Code)
+		case rules.CodeMaxLen:
+			setLen(1, c.Params["max"], c.Code)
+		case rules.CodeNotEmpty:
+			setLen(0, 1, c.Code)
+		case rules.CodeRegex:
+			set("pattern", c.Params["pattern"], c.Code)
+		case rules.CodePrefix:
+			if _, ok := s["pattern"]; !ok {
+				set("pattern", "^"+regexp.QuoteMeta(fmt.Sprint(c.Params["prefix"])), c.Code)
+			}
+		case rules.CodeSuffix:
+			if _, ok := s["pattern"]; !ok {
+				set("pattern", regexp.QuoteMeta(fmt.Sprint(c.Params["suffix"]))+"$", c.Code)
+			}
+		case value.CodeOneOf:
+			set("enum", enumValues(c.Params["values"]), c.Code)
+		}
+	}
+
+	if len(codes) > 0 {
+		s[CodesKeyword] = codes
+	}
+}
+
+// enumValues returns the values in their text form if they implement
+// encoding.TextMarshaler, like value.Enum.JSONSchema.
+func enumValues(values any) []any {
+	rv := reflect.ValueOf(values)
+	if rv.Kind() != reflect.Slice {
+		return nil
+	}
+
+	enum := make([]any, rv.Len())
+	for i := range enum {
+		v := rv.Index(i).Interface()
+		if m, ok := v.(encoding.TextMarshaler); ok {
+			if b, err := m.MarshalText(); err == nil {
+				v = string(b)
+			}
+		}
+
+		enum[i] = v
+	}
+
+	return enum
+}
+
+func isNumber(v any) bool {
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// indirect dereferences the pointers and interfaces. Nil pointers are
+// replaced with a new value, so that the schema of the type can be generated.
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		switch {
+		case v.Kind() == reflect.Interface && v.IsNil():
+			return reflect.Value{}
+		case v.IsNil():
+			v = reflect.New(v.Type().Elem())
+		}
+
+		v = v.Elem()
+	}
+
+	return v
+}
+
+func isNullable(t reflect.Type) bool {
+	if t.Kind() != reflect.Pointer {
+		t = reflect.PointerTo(t)
+	}
+
+	_, ok := t.MethodByName("IsNull")
+
+	return ok
+}
+
+// implements reports whether the type or its pointer implements the interface.
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || (t.Kind() != reflect.Interface && t.Kind() != reflect.Pointer && reflect.PointerTo(t).Implements(iface))
+}
+
+// addressable returns a pointer to the value, copying it if it is not
+// addressable, so that methods with pointer receivers can be called.
+func addressable(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.Pointer {
+		return v
+	}
+
+	if v.CanAddr() {
+		return v.Addr()
+	}
+
+	p := reflect.New(v.Type())
+	p.Elem().Set(v)
+
+	return p
+}
+
+func copySchema(s map[string]any) map[string]any {
+	c := make(map[string]any, len(s))
+	for k, v := range s {
+		c[k] = v
+	}
+
+	return c
+}
@@ -0,0 +1,151 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alextanhongpin/value"
+	"github.com/alextanhongpin/value/examples/colors"
+	"github.com/alextanhongpin/value/jsonschema"
+	"github.com/alextanhongpin/value/rules"
+)
+
+type role string
+
+var roles = value.NewEnum[role]("admin", "member")
+
+type address struct {
+	City *value.Value[string] `json:"city"`
+}
+
+func (a *address) Validate() error {
+	return value.ValidateStruct(a)
+}
+
+type category struct {
+	Name     string      `json:"name"`
+	Children []*category `json:"children,omitempty"`
+}
+
+type userDto struct {
+	Name      *value.Value[string]       `json:"name"`
+	Age       *value.Value[int]          `json:"age" value:"optional"`
+	Role      *value.Value[role]         `json:"role"`
+	Color     *value.Object[*colors.RGB] `json:"color"`
+	Address   *value.Object[*address]    `json:"address" value:"optional"`
+	Nickname  value.Nullable[string]     `json:"nickname" value:"optional"`
+	Tags      []string                   `json:"tags"`
+	Category  category                   `json:"category"`
+	CreatedAt time.Time                  `json:"createdAt"`
+	Secret    string                     `json:"-"`
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	dto := userDto{
+		Name: value.New("", value.Rules(rules.And(rules.MinLen[string](1), rules.MaxLen[string](100)), rules.Regex[string]("^[a-z]+$"))),
+		Age:  value.New(0, value.Rules(rules.Between(0, 150))),
+		Role: value.New[role]("", value.Rules[role](roles)),
+	}
+
+	schema, err := jsonschema.Generate(&dto)
+	if err != nil {
+		t.Fatalf("failed to generate: %s", err)
+	}
+
+	got, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{
+		"$defs": {
+			"address": {"properties": {"city": {"type": "string"}}, "required": ["city"], "type": "object"},
+			"category": {
+				"properties": {
+					"children": {"items": {"$ref": "#/$defs/category"}, "type": "array"},
+					"name": {"type": "string"}
+				},
+				"type": "object"
+			}
+		},
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"properties": {
+			"address": {"anyOf": [{"$ref": "#/$defs/address"}, {"type": "null"}]},
+			"age": {"maximum": 150, "minimum": 0, "type": ["integer", "null"], "x-codes": {"maximum": "between", "minimum": "between"}},
+			"category": {"$ref": "#/$defs/category"},
+			"color": {"pattern": "^rgb\\(\\s*\\d{1,3},\\s*\\d{1,3},\\s*\\d{1,3}\\)$", "type": "string"},
+			"createdAt": {"format": "date-time", "type": "string"},
+			"name": {"maxLength": 100, "minLength": 1, "pattern": "^[a-z]+$", "type": "string"},
+			"nickname": {"type": ["string", "null"]},
+			"role": {"enum": ["admin", "member"], "type": "string"},
+			"tags": {"items": {"type": "string"}, "type": "array"}
+		},
+		"required": ["name", "role", "color"],
+		"type": "object"
+	}`
+
+	var wantSchema any
+	if err := json.Unmarshal([]byte(want), &wantSchema); err != nil {
+		t.Fatal(err)
+	}
+
+	wantJSON, _ := json.Marshal(wantSchema)
+	if string(wantJSON) != string(got) {
+		t.Fatalf("expected %s, got %s", wantJSON, got)
+	}
+}
+
+type nameDto struct {
+	Name *value.Value[string] `json:"name"`
+}
+
+type pairDto struct {
+	A nameDto `json:"a"`
+	B nameDto `json:"b"`
+	C nameDto `json:"c"`
+}
+
+func TestGenerateInstanceRules(t *testing.T) {
+	t.Parallel()
+
+	dto := pairDto{
+		A: nameDto{Name: value.New("", value.Rules(rules.MaxLen[string](3)))},
+		B: nameDto{Name: value.New("", value.Rules(rules.MaxLen[string](99)))},
+	}
+
+	schema, err := jsonschema.Generate(&dto)
+	if err != nil {
+		t.Fatalf("failed to generate: %s", err)
+	}
+
+	got, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{
+		"$defs": {
+			"nameDto": {"properties": {"name": {"type": "string"}}, "required": ["name"], "type": "object"}
+		},
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"properties": {
+			"a": {"properties": {"name": {"maxLength": 3, "type": "string"}}, "required": ["name"], "type": "object"},
+			"b": {"properties": {"name": {"maxLength": 99, "type": "string"}}, "required": ["name"], "type": "object"},
+			"c": {"$ref": "#/$defs/nameDto"}
+		},
+		"type": "object"
+	}`
+
+	var wantSchema any
+	if err := json.Unmarshal([]byte(want), &wantSchema); err != nil {
+		t.Fatal(err)
+	}
+
+	wantJSON, _ := json.Marshal(wantSchema)
+	if string(wantJSON) != string(got) {
+		t.Fatalf("expected %s, got %s", wantJSON, got)
+	}
+}
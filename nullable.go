@@ -0,0 +1,183 @@
+package value
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrNull = errors.New("null value")
+
+// Nullable represents a tri-state value that distinguishes between a value
+// that is undefined (never set), explicitly null, and present.
+//
+// When decoding JSON, Nullable must be declared as a non-pointer field, since
+// encoding/json sets pointer fields to nil on null without calling
+// UnmarshalJSON. Tag the field with `json:",omitzero"` to omit undefined values
+// when encoding.
+type Nullable[T any] struct {
+	value T
+	dirty bool
+	null  bool
+}
+
+func NewNullable[T any](t T) Nullable[T] {
+	return Nullable[T]{
+		value: t,
+		dirty: true,
+	}
+}
+
+func Null[T any]() Nullable[T] {
+	return Nullable[T]{
+		dirty: true,
+		null:  true,
+	}
+}
+
+// IsZero returns true if the value is undefined.
+func (n *Nullable[T]) IsZero() bool {
+	return n == nil || !n.dirty
+}
+
+func (n *Nullable[T]) IsDefined() bool {
+	return !n.IsZero()
+}
+
+func (n *Nullable[T]) IsNull() bool {
+	return n.IsDefined() && n.null
+}
+
+// IsPresent returns true if the value is defined and not null.
+func (n *Nullable[T]) IsPresent() bool {
+	return n.IsDefined() && !n.null
+}
+
+func (n *Nullable[T]) Set(t T) error {
+	n.value = t
+	n.dirty = true
+	n.null = false
+
+	return nil
+}
+
+func (n *Nullable[T]) SetNull() {
+	var t T
+	n.value = t
+	n.dirty = true
+	n.null = true
+}
+
+// Unset resets the value to undefined.
+func (n *Nullable[T]) Unset() {
+	var t T
+	n.value = t
+	n.dirty = false
+	n.null = false
+}
+
+func (n *Nullable[T]) Get() (t T, isPresent bool) {
+	if !n.IsPresent() {
+		return
+	}
+
+	return n.value, true
+}
+
+func (n *Nullable[T]) MustGet() T {
+	if n.IsZero() {
+		panic(ErrNotSet)
+	}
+
+	if n.IsNull() {
+		panic(ErrNull)
+	}
+
+	return n.value
+}
+
+// Validate returns an error unless the value is present. If T is validatable,
+// the present value is validated too.
+func (n *Nullable[T]) Validate() error {
+	if n.IsZero() {
+		return ErrNotSet
+	}
+
+	if n.IsNull() {
+		return ErrNull
+	}
+
+	return n.validate()
+}
+
+func (n *Nullable[T]) MustValidate() {
+	if err := n.Validate(); err != nil {
+		panic(err)
+	}
+}
+
+func (n *Nullable[T]) Valid() bool {
+	return n.Validate() == nil
+}
+
+func (n *Nullable[T]) MustValid() {
+	n.MustValidate()
+}
+
+// ValidateOptional accepts undefined and null values, and validates present
+// values.
+func (n *Nullable[T]) ValidateOptional() error {
+	if !n.IsPresent() {
+		return nil
+	}
+
+	return n.validate()
+}
+
+func (n *Nullable[T]) Optional() bool {
+	return n.ValidateOptional() == nil
+}
+
+func (n *Nullable[T]) validate() error {
+	if v, ok := any(n.value).(validatable); ok {
+		return v.Validate()
+	}
+
+	return nil
+}
+
+func (n Nullable[T]) String() string {
+	if n.IsZero() {
+		return "NOT SET"
+	}
+
+	if n.IsNull() {
+		return "NULL"
+	}
+
+	return fmt.Sprint(n.value)
+}
+
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.IsPresent() {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(n.value)
+}
+
+func (n *Nullable[T]) UnmarshalJSON(raw []byte) error {
+	if bytes.Equal(raw, []byte("null")) {
+		n.SetNull()
+
+		return nil
+	}
+
+	var t T
+	if err := json.Unmarshal(raw, &t); err != nil {
+		return err
+	}
+
+	return n.Set(t)
+}
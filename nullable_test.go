@@ -0,0 +1,95 @@
+package value_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/alextanhongpin/value"
+)
+
+type patchUserDto struct {
+	Name value.Nullable[string] `json:"name,omitzero"`
+}
+
+func TestNullable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		raw      string
+		defined  bool
+		null     bool
+		validate error
+	}{
+		{"undefined", `{}`, false, false, value.ErrNotSet},
+		{"null", `{"name": null}`, true, true, value.ErrNull},
+		{"present", `{"name": "john"}`, true, false, nil},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var dto patchUserDto
+			if err := json.Unmarshal([]byte(tc.raw), &dto); err != nil {
+				t.Fatalf("failed to unmarshal: %s", err)
+			}
+
+			if got := dto.Name.IsDefined(); tc.defined != got {
+				t.Fatalf("expected defined %t, got %t", tc.defined, got)
+			}
+
+			if got := dto.Name.IsNull(); tc.null != got {
+				t.Fatalf("expected null %t, got %t", tc.null, got)
+			}
+
+			if err := dto.Name.Validate(); !errors.Is(err, tc.validate) {
+				t.Fatalf("expected %v, got %v", tc.validate, err)
+			}
+
+			if err := dto.Name.ValidateOptional(); err != nil {
+				t.Fatalf("expected optional, got %s", err)
+			}
+
+			b, err := json.Marshal(dto)
+			if err != nil {
+				t.Fatalf("failed to marshal: %s", err)
+			}
+
+			var want, got any
+			_ = json.Unmarshal([]byte(tc.raw), &want)
+			_ = json.Unmarshal(b, &got)
+			if a, b := mustJSON(t, want), mustJSON(t, got); a != b {
+				t.Fatalf("expected %s, got %s", a, b)
+			}
+
+			// Each state survives the round trip.
+			var rt patchUserDto
+			if err := json.Unmarshal(b, &rt); err != nil {
+				t.Fatalf("failed to unmarshal: %s", err)
+			}
+
+			if got := rt.Name.IsDefined(); tc.defined != got {
+				t.Fatalf("expected defined %t after round trip, got %t", tc.defined, got)
+			}
+
+			if got := rt.Name.IsNull(); tc.null != got {
+				t.Fatalf("expected null %t after round trip, got %t", tc.null, got)
+			}
+		})
+	}
+}
+
+func mustJSON(t *testing.T, v any) string {
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("failed to marshal: %s", err)
+	}
+
+	return string(b)
+}
@@ -96,6 +96,9 @@ func (o *Object[T]) MarshalJSON() ([]byte, error) {
 	return json.Marshal(o.value)
 }
 
+// UnmarshalJSON implements json.Unmarshaler. The object is not validated, so
+// that the validation can be deferred. Use a JSONDecoder with the Eager or
+// Collect policy to validate the decoded objects.
 func (o *Object[T]) UnmarshalJSON(raw []byte) error {
 	if bytes.Equal(raw, []byte("null")) {
 		return nil
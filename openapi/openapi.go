@@ -0,0 +1,160 @@
+// Package openapi generates the components of an OpenAPI 3.1 document from
+// the DTOs, so that the API docs stay in sync with the rules enforced in Go.
+//
+//	doc := openapi.New("Users API", "1.0.0")
+//	doc.Register("CreateUserDto", &CreateUserDto{
+//		Name: value.New("", value.Rules(rules.MaxLen[string](100))),
+//	})
+//	doc.WriteFile("openapi.json")
+//
+// OpenAPI 3.1 schemas are JSON Schema Draft 2020-12, so the schemas are
+// generated with the jsonschema package. A ValidationError response with the
+// problem details of httpx is included.
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/alextanhongpin/value/httpx"
+	"github.com/alextanhongpin/value/jsonschema"
+)
+
+// Version is the OpenAPI version of the generated documents.
+const Version = "3.1.0"
+
+const (
+	// ProblemSchema is the name of the problem details schema.
+	ProblemSchema = "Problem"
+	// ValidationErrorResponse is the name of the validation error response.
+	ValidationErrorResponse = "ValidationError"
+)
+
+// ErrDuplicateName is returned when a name is registered twice, or is the name
+// of the problem details schema.
+var ErrDuplicateName = errors.New("openapi: duplicate schema name")
+
+// Document is an OpenAPI document with the components of the registered
+// DTOs.
+type Document struct {
+	Title   string
+	Version string
+
+	dtos []dto
+}
+
+type dto struct {
+	name string
+	v    any
+}
+
+func New(title, version string) *Document {
+	return &Document{
+		Title:   title,
+		Version: version,
+	}
+}
+
+// Register adds the schema of the DTO to the components under the name. The
+// name must be unique, and must not be ProblemSchema. Like
+// jsonschema.Generate, the DTO is a value, so that the rules attached to its
+// fields are included.
+func (d *Document) Register(name string, v any) {
+	d.dtos = append(d.dtos, dto{name: name, v: v})
+}
+
+// Components returns the components/schemas and components/responses of the
+// registered DTOs.
+func (d *Document) Components() (map[string]any, error) {
+	g := jsonschema.NewGenerator()
+	g.RefPrefix = "#/components/schemas/"
+
+	// The nested structs of a registered type refer to its component. Other
+	// types that share a registered name are numbered, e.g. Address2, so that
+	// their references stay correct.
+	g.Reserve(ProblemSchema)
+	names := map[string]bool{ProblemSchema: true}
+	for _, dto := range d.dtos {
+		if names[dto.name] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateName, dto.name)
+		}
+		names[dto.name] = true
+
+		if err := g.Define(dto.name, dto.v); err != nil {
+			return nil, err
+		}
+	}
+
+	schemas := make(map[string]any)
+
+	problem, err := g.Schema(&httpx.Problem{})
+	if err != nil {
+		return nil, err
+	}
+
+	problem["required"] = []string{"type", "title", "status"}
+	schemas[ProblemSchema] = problem
+
+	for name, s := range g.Defs() {
+		schemas[name] = s
+	}
+
+	return map[string]any{
+		"schemas": schemas,
+		"responses": map[string]any{
+			ValidationErrorResponse: map[string]any{
+				"description": "The request is invalid. The invalid fields are listed in invalid-params.",
+				"content": map[string]any{
+					httpx.ContentTypeProblem: map[string]any{
+						"schema": map[string]any{"$ref": "#/components/schemas/" + ProblemSchema},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func (d *Document) MarshalJSON() ([]byte, error) {
+	components, err := d.Components()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(map[string]any{
+		"openapi": Version,
+		"info": map[string]any{
+			"title":   d.Title,
+			"version": d.Version,
+		},
+		"components": components,
+	})
+}
+
+// WriteTo writes the document as indented JSON.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	b, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := w.Write(append(b, '\n'))
+
+	return int64(n), err
+}
+
+func (d *Document) WriteFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := d.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,161 @@
+package openapi_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/alextanhongpin/value"
+	"github.com/alextanhongpin/value/openapi"
+	"github.com/alextanhongpin/value/rules"
+)
+
+type address struct {
+	City *value.Value[string] `json:"city"`
+}
+
+func (a *address) Validate() error {
+	return value.ValidateStruct(a)
+}
+
+type createUserDto struct {
+	Name    *value.Value[string]    `json:"name"`
+	Address *value.Object[*address] `json:"address"`
+}
+
+type document struct {
+	OpenAPI    string `json:"openapi"`
+	Components struct {
+		Schemas   map[string]map[string]any `json:"schemas"`
+		Responses map[string]struct {
+			Content map[string]struct {
+				Schema map[string]any `json:"schema"`
+			} `json:"content"`
+		} `json:"responses"`
+	} `json:"components"`
+}
+
+func TestDocument(t *testing.T) {
+	t.Parallel()
+
+	doc := openapi.New("Users API", "1.0.0")
+	doc.Register("CreateUserDto", &createUserDto{
+		Name: value.New("", value.Rules(rules.MaxLen[string](100))),
+	})
+
+	var b bytes.Buffer
+	if _, err := doc.WriteTo(&b); err != nil {
+		t.Fatalf("failed to write: %s", err)
+	}
+
+	var got document
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal: %s", err)
+	}
+
+	if got.OpenAPI != openapi.Version {
+		t.Fatalf("expected %s, got %s", openapi.Version, got.OpenAPI)
+	}
+
+	schemas := got.Components.Schemas
+	for _, name := range []string{"CreateUserDto", "address", openapi.ProblemSchema, "InvalidParam"} {
+		if schemas[name] == nil {
+			t.Fatalf("expected schema %s, got %v", name, schemas)
+		}
+	}
+
+	props := schemas["CreateUserDto"]["properties"].(map[string]any)
+	if ref := props["address"].(map[string]any)["$ref"]; ref != "#/components/schemas/address" {
+		t.Fatalf("expected component reference, got %v", ref)
+	}
+
+	if max := props["name"].(map[string]any)["maxLength"]; max != float64(100) {
+		t.Fatalf("expected 100, got %v", max)
+	}
+
+	content := got.Components.Responses[openapi.ValidationErrorResponse].Content["application/problem+json"]
+	if ref := content.Schema["$ref"]; ref != "#/components/schemas/Problem" {
+		t.Fatalf("expected problem reference, got %v", ref)
+	}
+}
+
+type other struct {
+	Note *value.Value[string] `json:"note"`
+}
+
+type Problem struct {
+	Reason *value.Value[string] `json:"reason"`
+}
+
+type report struct {
+	Problem Problem `json:"problem"`
+}
+
+func TestDocumentNameClash(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nested struct", func(t *testing.T) {
+		doc := openapi.New("Users API", "1.0.0")
+		doc.Register("CreateUserDto", &createUserDto{})
+		doc.Register("address", &other{})
+		doc.Register("Report", &report{})
+
+		components, err := doc.Components()
+		if err != nil {
+			t.Fatalf("failed to generate: %s", err)
+		}
+
+		schemas := components["schemas"].(map[string]any)
+		props := schemas["CreateUserDto"].(map[string]any)["properties"].(map[string]any)
+		if ref := props["address"].(map[string]any)["$ref"]; ref != "#/components/schemas/address2" {
+			t.Fatalf("expected %s, got %v", "#/components/schemas/address2", ref)
+		}
+
+		if _, ok := schemas["address"].(map[string]any)["properties"].(map[string]any)["note"]; !ok {
+			t.Fatalf("expected registered schema, got %v", schemas["address"])
+		}
+
+		props = schemas["Report"].(map[string]any)["properties"].(map[string]any)
+		if ref := props["problem"].(map[string]any)["$ref"]; ref != "#/components/schemas/Problem2" {
+			t.Fatalf("expected %s, got %v", "#/components/schemas/Problem2", ref)
+		}
+	})
+
+	t.Run("registered type", func(t *testing.T) {
+		doc := openapi.New("Users API", "1.0.0")
+		doc.Register("CreateUserDto", &createUserDto{})
+		doc.Register("Address", &address{
+			City: value.New("", value.Rules(rules.NotEmpty[string]())),
+		})
+
+		components, err := doc.Components()
+		if err != nil {
+			t.Fatalf("failed to generate: %s", err)
+		}
+
+		schemas := components["schemas"].(map[string]any)
+		props := schemas["CreateUserDto"].(map[string]any)["properties"].(map[string]any)
+		if ref := props["address"].(map[string]any)["$ref"]; ref != "#/components/schemas/Address" {
+			t.Fatalf("expected %s, got %v", "#/components/schemas/Address", ref)
+		}
+
+		for _, name := range []string{"address", "Address2"} {
+			if s, ok := schemas[name]; ok {
+				t.Fatalf("expected no schema %s, got %v", name, s)
+			}
+		}
+	})
+
+	t.Run("registered names", func(t *testing.T) {
+		for _, name := range []string{openapi.ProblemSchema, "CreateUserDto"} {
+			doc := openapi.New("Users API", "1.0.0")
+			doc.Register("CreateUserDto", &createUserDto{})
+			doc.Register(name, &other{})
+
+			if _, err := doc.Components(); !errors.Is(err, openapi.ErrDuplicateName) {
+				t.Fatalf("expected %s, got %v", openapi.ErrDuplicateName, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,361 @@
+package value
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+var (
+	containerType       = reflect.TypeOf((*Container)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// ApplyMergePatch applies the JSON merge patch (RFC 7386) to dst, which must be
+// a pointer to a struct, and returns the sorted paths of the fields that
+// changed.
+//
+//	// {"name": "john", "bio": null, "address": {"city": "Singapore"}}
+//	changed, err := value.ApplyMergePatch(&user, patch)
+//	// changed is ["address.city", "bio", "name"].
+//
+// Only the fields present in the patch are touched, and keys that do not match
+// a field are ignored:
+//   - null sets a Nullable to null, resets a Value or Object to not set, and
+//     sets other fields to their zero value. Rules are kept.
+//   - objects are merged into structs, maps and the structs contained in Value,
+//     Object and Nullable fields, recursively.
+//   - other values replace the field as json.Unmarshal does, so the rules of
+//     Values are enforced.
+//
+// The touched Value, Object and Nullable fields are validated when they are
+// set. Errors are returned as an ErrorMap keyed by the field path, and the
+// other fields are still patched, so dst should be discarded on error.
+func ApplyMergePatch(dst any, patch []byte) ([]string, error) {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %T", ErrNotStruct, dst)
+	}
+
+	if !isJSONObject(patch) {
+		return nil, fmt.Errorf("%w: merge patch is not an object", ErrInvalidValue)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &fields); err != nil {
+		return nil, err
+	}
+
+	p := &patcher{errs: make(ErrorMap)}
+	p.mergeStruct(rv.Elem(), fields, "")
+	sort.Strings(p.changed)
+
+	if len(p.errs) > 0 {
+		return p.changed, p.errs
+	}
+
+	return p.changed, nil
+}
+
+type patcher struct {
+	changed []string
+	errs    ErrorMap
+}
+
+// merge applies the patch to the addressable v, and records whether it
+// changed.
+func (p *patcher) merge(v reflect.Value, raw json.RawMessage, path string) {
+	before := snapshot(v)
+
+	merged, err := p.apply(v, raw, path)
+	if err != nil {
+		p.errs.add(WithField(path, err))
+
+		return
+	}
+
+	// Merged fields record the changes of their own fields, so only a change
+	// of state is recorded for them, e.g. an Object that becomes set.
+	after := snapshot(v)
+	if merged && before.state != after.state || !merged && before != after {
+		p.changed = append(p.changed, path)
+	}
+
+	if c, ok := containerOf(v); ok && after.state == stateSet {
+		if vv, ok := c.(validatable); ok {
+			p.errs.add(WithField(path, vv.Validate()))
+		}
+	}
+}
+
+// apply applies the patch to v, and reports whether it was merged rather than
+// replaced.
+func (p *patcher) apply(v reflect.Value, raw json.RawMessage, path string) (bool, error) {
+	switch {
+	case bytes.Equal(bytes.TrimSpace(raw), []byte("null")):
+		resetField(v)
+
+		return false, nil
+	case !isJSONObject(raw) || !mergeable(v.Type()):
+		return false, json.Unmarshal(raw, v.Addr().Interface())
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return false, err
+	}
+
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		v = v.Elem()
+	}
+
+	c, ok := v.Addr().Interface().(Container)
+	if !ok {
+		p.mergeInto(v, fields, path)
+
+		return true, nil
+	}
+
+	// Like RFC 7386, a container that is not set or null is merged as if it
+	// held an empty object.
+	elem := addressable(reflect.ValueOf(c.Any()))
+	p.mergeInto(elem, fields, path)
+
+	return true, c.SetAny(elem.Interface())
+}
+
+// mergeInto merges the fields into the struct or map v, which may be behind
+// pointers.
+func (p *patcher) mergeInto(v reflect.Value, fields map[string]json.RawMessage, path string) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		v = v.Elem()
+	}
+
+	if v.Kind() == reflect.Map {
+		p.mergeMap(v, fields, path)
+	} else {
+		p.mergeStruct(v, fields, path)
+	}
+}
+
+func (p *patcher) mergeStruct(v reflect.Value, fields map[string]json.RawMessage, path string) {
+	for _, name := range sortedKeys(fields) {
+		f, ok := lookupField(v, name)
+		if !ok {
+			continue
+		}
+
+		p.merge(f, fields[name], joinPath(path, name))
+	}
+}
+
+func (p *patcher) mergeMap(m reflect.Value, fields map[string]json.RawMessage, path string) {
+	if m.IsNil() {
+		m.Set(reflect.MakeMap(m.Type()))
+	}
+
+	for _, name := range sortedKeys(fields) {
+		key := reflect.ValueOf(name).Convert(m.Type().Key())
+		old := m.MapIndex(key)
+
+		raw := fields[name]
+		if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+			if old.IsValid() {
+				m.SetMapIndex(key, reflect.Value{})
+				p.changed = append(p.changed, joinPath(path, name))
+			}
+
+			continue
+		}
+
+		elem := reflect.New(m.Type().Elem()).Elem()
+		if old.IsValid() {
+			elem.Set(old)
+		}
+
+		p.merge(elem, raw, joinPath(path, name))
+		m.SetMapIndex(key, elem)
+	}
+}
+
+// lookupField returns the field with the json name. Like encoding/json, the
+// fields of embedded structs are promoted.
+func lookupField(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+
+	var embedded []int
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !promoted(f) {
+			continue
+		}
+
+		fname, ok := fieldName(f)
+		if !ok {
+			continue
+		}
+
+		if fname == "" {
+			if promoted(f) {
+				embedded = append(embedded, i)
+			}
+
+			continue
+		}
+
+		if fname == name {
+			return v.Field(i), true
+		}
+	}
+
+	for _, i := range embedded {
+		f := v.Field(i)
+		if f.Kind() == reflect.Pointer {
+			if f.IsNil() {
+				if !f.CanSet() {
+					continue
+				}
+
+				f.Set(reflect.New(f.Type().Elem()))
+			}
+
+			f = f.Elem()
+		}
+
+		if f, ok := lookupField(f, name); ok {
+			return f, true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// mergeable reports whether an object patch is merged into values of the type,
+// rather than replacing them.
+func mergeable(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer && !t.Implements(containerType) {
+		t = t.Elem()
+	}
+
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if reflect.PointerTo(t).Implements(containerType) {
+		a := reflect.New(t).Interface().(Container).Any()
+		if a == nil {
+			return false
+		}
+
+		return mergeable(reflect.TypeOf(a))
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return !reflect.PointerTo(t).Implements(jsonUnmarshalerType)
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String
+	default:
+		return false
+	}
+}
+
+// resetField resets v for a null patch. Nullable fields are set to null,
+// which is allocated if needed.
+func resetField(v reflect.Value) {
+	target := v
+	if v.Kind() != reflect.Pointer {
+		target = v.Addr()
+	} else if v.IsNil() {
+		if _, ok := v.Interface().(interface{ SetNull() }); !ok {
+			return
+		}
+
+		v.Set(reflect.New(v.Type().Elem()))
+		target = v
+	}
+
+	switch t := target.Interface().(type) {
+	case interface{ SetNull() }:
+		t.SetNull()
+	case interface{ reset() }:
+		t.reset()
+	default:
+		v.Set(reflect.Zero(v.Type()))
+	}
+}
+
+// state is the presence of a Value, Object or Nullable field.
+type state int
+
+const (
+	stateNotSet state = iota
+	stateNull
+	stateSet
+)
+
+// fieldState is compared before and after a field is patched.
+type fieldState struct {
+	state state
+	json  string
+}
+
+func snapshot(v reflect.Value) fieldState {
+	var s fieldState
+	if c, ok := containerOf(v); ok {
+		switch n, ok := c.(interface{ IsNull() bool }); {
+		case c.IsZero():
+			s.state = stateNotSet
+		case ok && n.IsNull():
+			s.state = stateNull
+		default:
+			s.state = stateSet
+		}
+	}
+
+	b, _ := json.Marshal(v.Addr().Interface())
+	s.json = string(b)
+
+	return s
+}
+
+// containerOf returns the Value, Object or Nullable that v holds, which may be
+// a nil pointer.
+func containerOf(v reflect.Value) (Container, bool) {
+	if v.Kind() == reflect.Pointer {
+		c, ok := v.Interface().(Container)
+
+		return c, ok
+	}
+
+	c, ok := v.Addr().Interface().(Container)
+
+	return c, ok
+}
+
+func isJSONObject(raw []byte) bool {
+	raw = bytes.TrimSpace(raw)
+
+	return len(raw) > 0 && raw[0] == '{'
+}
+
+func sortedKeys(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
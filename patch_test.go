@@ -0,0 +1,143 @@
+package value_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/alextanhongpin/value"
+	"github.com/alextanhongpin/value/rules"
+)
+
+type postalAddress struct {
+	City       *value.Value[string] `json:"city"`
+	PostalCode *value.Value[string] `json:"postalCode"`
+}
+
+func (a *postalAddress) Validate() error {
+	return value.ValidateStruct(a)
+}
+
+type account struct {
+	Name    *value.Value[string]          `json:"name"`
+	Age     value.Value[int]              `json:"age"`
+	Bio     value.Nullable[string]        `json:"bio"`
+	Address *value.Object[*postalAddress] `json:"address"`
+	Labels  map[string]string             `json:"labels"`
+	Tags    []string                      `json:"tags"`
+}
+
+func newAccount(t *testing.T) *account {
+	t.Helper()
+
+	a := &account{
+		Name: value.New("john", value.Rules(rules.MaxLen[string](8))),
+		Address: value.NewObject(&postalAddress{
+			City:       value.New("Singapore"),
+			PostalCode: value.New("123456"),
+		}),
+		Labels: map[string]string{"team": "a", "role": "dev"},
+		Tags:   []string{"a", "b"},
+	}
+	if err := a.Age.Set(20); err != nil {
+		t.Fatalf("failed to set age: %s", err)
+	}
+	if err := a.Bio.Set("hello"); err != nil {
+		t.Fatalf("failed to set bio: %s", err)
+	}
+
+	return a
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("merge", func(t *testing.T) {
+		a := newAccount(t)
+
+		patch := `{
+			"name": "jane",
+			"age": 20,
+			"bio": null,
+			"address": {"city": "Tokyo"},
+			"labels": {"team": null, "level": "senior"},
+			"tags": ["c"],
+			"unknown": 1
+		}`
+
+		changed, err := value.ApplyMergePatch(a, []byte(patch))
+		if err != nil {
+			t.Fatalf("failed to apply patch: %s", err)
+		}
+
+		want := []string{"address.city", "bio", "labels.level", "labels.team", "name", "tags"}
+		if !reflect.DeepEqual(changed, want) {
+			t.Fatalf("expected %v, got %v", want, changed)
+		}
+
+		if !a.Bio.IsNull() {
+			t.Fatalf("expected bio to be null, got %v", a.Bio)
+		}
+
+		address := a.Address.MustGet()
+		if city, postalCode := address.City.MustGet(), address.PostalCode.MustGet(); city != "Tokyo" || postalCode != "123456" {
+			t.Fatalf("expected %s %s, got %s %s", "Tokyo", "123456", city, postalCode)
+		}
+
+		wantLabels := map[string]string{"role": "dev", "level": "senior"}
+		if !reflect.DeepEqual(a.Labels, wantLabels) {
+			t.Fatalf("expected %v, got %v", wantLabels, a.Labels)
+		}
+	})
+
+	t.Run("reset keeps rules", func(t *testing.T) {
+		a := newAccount(t)
+
+		changed, err := value.ApplyMergePatch(a, []byte(`{"name": null, "address": null}`))
+		if err != nil {
+			t.Fatalf("failed to apply patch: %s", err)
+		}
+
+		if want := []string{"address", "name"}; !reflect.DeepEqual(changed, want) {
+			t.Fatalf("expected %v, got %v", want, changed)
+		}
+
+		if !a.Name.IsZero() || !a.Address.IsZero() {
+			t.Fatalf("expected not set, got %v and %v", a.Name, a.Address)
+		}
+
+		if _, err := value.ApplyMergePatch(a, []byte(`{"name": "jessica-jones"}`)); !errors.Is(err, value.ErrInvalidValue) {
+			t.Fatalf("expected %s, got %v", value.ErrInvalidValue, err)
+		}
+	})
+
+	t.Run("validates touched objects", func(t *testing.T) {
+		var a account
+
+		changed, err := value.ApplyMergePatch(&a, []byte(`{"address": {"city": "Tokyo"}, "age": "x"}`))
+
+		var errs value.ErrorMap
+		if !errors.As(err, &errs) {
+			t.Fatalf("expected %T, got %v", errs, err)
+		}
+
+		if len(errs) != 2 || !errors.Is(errs["address.postalCode"], value.ErrNotSet) || errs["age"] == nil {
+			t.Fatalf("expected errors at %s and %s, got %s", "address.postalCode", "age", errs)
+		}
+
+		if want := []string{"address", "address.city"}; !reflect.DeepEqual(changed, want) {
+			t.Fatalf("expected %v, got %v", want, changed)
+		}
+	})
+
+	t.Run("invalid patch", func(t *testing.T) {
+		var a account
+		if _, err := value.ApplyMergePatch(&a, []byte(`[]`)); !errors.Is(err, value.ErrInvalidValue) {
+			t.Fatalf("expected %s, got %v", value.ErrInvalidValue, err)
+		}
+
+		if _, err := value.ApplyMergePatch(a, []byte(`{}`)); !errors.Is(err, value.ErrNotStruct) {
+			t.Fatalf("expected %s, got %v", value.ErrNotStruct, err)
+		}
+	})
+}
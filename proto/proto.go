@@ -0,0 +1,569 @@
+// Package proto encodes structs with Value and Object fields in the proto3
+// wire format, without generated code.
+//
+//	type UserDto struct {
+//		Name    *value.Value[string]    `proto:"1"`
+//		Age     *value.Value[int]       `proto:"2"`
+//		Address *value.Object[*Address] `proto:"3"`
+//	}
+//
+// The fields are numbered with the `proto` tag, and fields without it are
+// skipped. A Value or Object that is not set is absent on the wire, and is
+// decoded as not set.
+//
+// Values of scalar types follow the semantics of the google.protobuf wrapper
+// types, e.g. Value[int64] is encoded like Int64Value, so that a value set to
+// zero is told apart from a value that is not set. Objects and Values of
+// struct types are encoded as nested messages.
+//
+// The scalar types map to proto3 types as follows:
+//
+//   - bool: bool
+//   - int, int8, int16, int32, int64: int64 (int32 is wire compatible)
+//   - uint, uint8, uint16, uint32, uint64: uint64
+//   - float32: float
+//   - float64: double
+//   - string, []byte and encoding.TextMarshaler: string or bytes
+//
+// Slices are repeated fields, and numeric slices are packed. Maps and
+// Nullable are not supported, since wrappers cannot tell null from absent.
+package proto
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"sync"
+
+	"github.com/alextanhongpin/value"
+)
+
+var ErrUnsupported = errors.New("proto: unsupported type")
+
+var (
+	containerType       = reflect.TypeOf((*value.Container)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Marshal encodes the struct v as a message.
+func Marshal(v any) ([]byte, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %T", value.ErrNotStruct, v)
+	}
+
+	return appendMessage(nil, rv)
+}
+
+// Unmarshal decodes the message into v, which must be a pointer to a struct.
+// Unknown fields are skipped. Like the JSON decoding, the rules of Values are
+// enforced, while Objects are not validated.
+func Unmarshal(b []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %T", value.ErrNotStruct, v)
+	}
+
+	return decodeMessage(b, rv.Elem())
+}
+
+// The field numbers that protoc accepts are 1 to maxFieldNumber, except for
+// the range reserved for the protobuf implementation.
+const (
+	maxFieldNumber     = 1<<29 - 1
+	firstReservedField = 19000
+	lastReservedField  = 19999
+)
+
+type fieldInfo struct {
+	num   int
+	index []int
+	name  string
+}
+
+var messageFields sync.Map // map[reflect.Type][]fieldInfo
+
+func fieldsOf(t reflect.Type) ([]fieldInfo, error) {
+	if fields, ok := messageFields.Load(t); ok {
+		return fields.([]fieldInfo), nil
+	}
+
+	var fields []fieldInfo
+	seen := make(map[int]string)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok := f.Tag.Lookup("proto")
+		if !ok || !f.IsExported() {
+			continue
+		}
+
+		num, err := strconv.Atoi(tag)
+		if err != nil || num <= 0 || num > maxFieldNumber {
+			return nil, fmt.Errorf("proto: invalid field number %q on %s.%s", tag, t, f.Name)
+		}
+
+		if num >= firstReservedField && num <= lastReservedField {
+			return nil, fmt.Errorf("proto: field number %d on %s.%s is reserved", num, t, f.Name)
+		}
+
+		if other, ok := seen[num]; ok {
+			return nil, fmt.Errorf("proto: field number %d is used by %s.%s and %s.%s", num, t, other, t, f.Name)
+		}
+		seen[num] = f.Name
+
+		fields = append(fields, fieldInfo{num: num, index: f.Index, name: f.Name})
+	}
+
+	actual, _ := messageFields.LoadOrStore(t, fields)
+
+	return actual.([]fieldInfo), nil
+}
+
+func appendMessage(b []byte, v reflect.Value) ([]byte, error) {
+	fields, err := fieldsOf(v.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range fields {
+		b, err = appendField(b, f.num, v.FieldByIndex(f.index))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.name, err)
+		}
+	}
+
+	return b, nil
+}
+
+// appendField appends the field, unless it is absent.
+func appendField(b []byte, num int, v reflect.Value) ([]byte, error) {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return b, nil
+		}
+
+		if isMessage(v.Type().Elem()) {
+			// Pointers to messages are present, even if they are empty.
+			payload, err := appendMessage(nil, v.Elem())
+			if err != nil {
+				return nil, err
+			}
+
+			return appendBytes(b, num, payload), nil
+		}
+
+		return appendField(b, num, v.Elem())
+	}
+
+	if c, ok := container(v); ok {
+		return appendContainer(b, num, c)
+	}
+
+	switch {
+	case isMessage(v.Type()):
+		payload, err := appendMessage(nil, v)
+		if err != nil || len(payload) == 0 {
+			return b, err
+		}
+
+		return appendBytes(b, num, payload), nil
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8:
+		return appendRepeated(b, num, v)
+	default:
+		return appendScalar(b, num, v, true)
+	}
+}
+
+func appendContainer(b []byte, num int, c value.Container) ([]byte, error) {
+	if isNullable(c) {
+		return nil, fmt.Errorf("%w: %T", ErrUnsupported, c)
+	}
+
+	if c.IsZero() {
+		return b, nil
+	}
+
+	v := reflect.ValueOf(c.Any())
+	if !v.IsValid() {
+		return nil, fmt.Errorf("%w: nil %T", ErrUnsupported, c)
+	}
+
+	if t := indirectType(v.Type()); isMessage(t) {
+		payload, err := appendMessage(nil, reflect.Indirect(v))
+		if err != nil {
+			return nil, err
+		}
+
+		return appendBytes(b, num, payload), nil
+	}
+
+	// The wrapper message holds the value in field 1, which is omitted when
+	// it is zero.
+	payload, err := appendScalar(nil, 1, reflect.Indirect(v), true)
+	if err != nil {
+		return nil, err
+	}
+
+	return appendBytes(b, num, payload), nil
+}
+
+func appendRepeated(b []byte, num int, v reflect.Value) ([]byte, error) {
+	if v.Len() == 0 {
+		return b, nil
+	}
+
+	if isPackable(v.Type().Elem()) {
+		var payload []byte
+		for i := 0; i < v.Len(); i++ {
+			payload = appendNumber(payload, v.Index(i))
+		}
+
+		return appendBytes(b, num, payload), nil
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		elem := v.Index(i)
+
+		var err error
+		if isMessage(indirectType(elem.Type())) {
+			var payload []byte
+			payload, err = appendMessage(nil, reflect.Indirect(elem))
+			b = appendBytes(b, num, payload)
+		} else if _, ok := container(elem); ok || elem.Kind() == reflect.Pointer {
+			b, err = appendField(b, num, elem)
+		} else {
+			// Repeated elements are present, even if they are zero.
+			b, err = appendScalar(b, num, elem, false)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return b, nil
+}
+
+// appendScalar appends the scalar field. If omitZero, zero values are
+// omitted, as in proto3.
+func appendScalar(b []byte, num int, v reflect.Value, omitZero bool) ([]byte, error) {
+	if omitZero && v.IsZero() {
+		return b, nil
+	}
+
+	if v.Type().Implements(textMarshalerType) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, err
+		}
+
+		return appendBytes(b, num, text), nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return appendBytes(b, num, []byte(v.String())), nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return appendBytes(b, num, v.Bytes()), nil
+		}
+	case reflect.Float32:
+		return appendNumber(appendTag(b, num, wireFixed32), v), nil
+	case reflect.Float64:
+		return appendNumber(appendTag(b, num, wireFixed64), v), nil
+	default:
+		if isPackable(v.Type()) {
+			return appendNumber(appendTag(b, num, wireVarint), v), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnsupported, v.Type())
+}
+
+// appendNumber appends the number without a tag.
+func appendNumber(b []byte, v reflect.Value) []byte {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return appendVarint(b, 1)
+		}
+
+		return appendVarint(b, 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return appendVarint(b, uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return appendVarint(b, v.Uint())
+	case reflect.Float32:
+		return appendFixed32(b, math.Float32bits(float32(v.Float())))
+	case reflect.Float64:
+		return appendFixed64(b, math.Float64bits(v.Float()))
+	default:
+		panic(fmt.Sprintf("proto: %s is not a number", v.Type()))
+	}
+}
+
+func decodeMessage(b []byte, v reflect.Value) error {
+	fields, err := fieldsOf(v.Type())
+	if err != nil {
+		return err
+	}
+
+	byNum := make(map[int]fieldInfo, len(fields))
+	for _, f := range fields {
+		byNum[f.num] = f
+	}
+
+	for len(b) > 0 {
+		var wf field
+		wf, b, err = consumeField(b)
+		if err != nil {
+			return err
+		}
+
+		f, ok := byNum[wf.num]
+		if !ok {
+			continue
+		}
+
+		if err := decodeField(v.FieldByIndex(f.index), wf); err != nil {
+			return fmt.Errorf("%s: %w", f.name, err)
+		}
+	}
+
+	return nil
+}
+
+func decodeField(v reflect.Value, wf field) error {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		return decodeField(v.Elem(), wf)
+	}
+
+	if c, ok := container(v); ok {
+		return decodeContainer(c, wf)
+	}
+
+	switch {
+	case isMessage(v.Type()):
+		if wf.typ != wireBytes {
+			return wireTypeError(wf)
+		}
+
+		return decodeMessage(wf.b, v)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8:
+		return decodeRepeated(v, wf)
+	default:
+		return decodeScalar(v, wf)
+	}
+}
+
+func decodeContainer(c value.Container, wf field) error {
+	if isNullable(c) {
+		return fmt.Errorf("%w: %T", ErrUnsupported, c)
+	}
+
+	if wf.typ != wireBytes {
+		return wireTypeError(wf)
+	}
+
+	a := c.Any()
+	if a == nil {
+		return fmt.Errorf("%w: nil %T", ErrUnsupported, c)
+	}
+
+	// The contained value is decoded into a copy, so that the rules of a
+	// Value are enforced by SetAny.
+	elem := reflect.New(reflect.TypeOf(a)).Elem()
+	elem.Set(reflect.ValueOf(a))
+
+	target := elem
+	if target.Kind() == reflect.Pointer {
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+
+		target = target.Elem()
+	}
+
+	if isMessage(target.Type()) {
+		if err := decodeMessage(wf.b, target); err != nil {
+			return err
+		}
+
+		return c.SetAny(elem.Interface())
+	}
+
+	// An empty wrapper holds the zero value.
+	b := wf.b
+	for len(b) > 0 {
+		var (
+			inner field
+			err   error
+		)
+		inner, b, err = consumeField(b)
+		if err != nil {
+			return err
+		}
+
+		if inner.num != 1 {
+			continue
+		}
+
+		if err := decodeScalar(target, inner); err != nil {
+			return err
+		}
+	}
+
+	return c.SetAny(elem.Interface())
+}
+
+func decodeRepeated(v reflect.Value, wf field) error {
+	et := v.Type().Elem()
+	if wf.typ == wireBytes && isPackable(et) {
+		typ := wireVarint
+		switch et.Kind() {
+		case reflect.Float32:
+			typ = wireFixed32
+		case reflect.Float64:
+			typ = wireFixed64
+		}
+
+		for b := wf.b; len(b) > 0; {
+			var (
+				n   uint64
+				err error
+			)
+			n, b, err = consumeNumber(b, typ)
+			if err != nil {
+				return err
+			}
+
+			elem := reflect.New(et).Elem()
+			if err := decodeScalar(elem, field{num: wf.num, typ: typ, n: n}); err != nil {
+				return err
+			}
+			v.Set(reflect.Append(v, elem))
+		}
+
+		return nil
+	}
+
+	elem := reflect.New(et).Elem()
+	if err := decodeField(elem, wf); err != nil {
+		return err
+	}
+	v.Set(reflect.Append(v, elem))
+
+	return nil
+}
+
+func decodeScalar(v reflect.Value, wf field) error {
+	if reflect.PointerTo(v.Type()).Implements(textUnmarshalerType) {
+		if wf.typ != wireBytes {
+			return wireTypeError(wf)
+		}
+
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText(wf.b)
+	}
+
+	want := wireVarint
+	switch v.Kind() {
+	case reflect.String, reflect.Slice:
+		want = wireBytes
+	case reflect.Float32:
+		want = wireFixed32
+	case reflect.Float64:
+		want = wireFixed64
+	}
+
+	if wf.typ != want {
+		return wireTypeError(wf)
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(wf.n != 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := int64(wf.n)
+		if v.OverflowInt(n) {
+			return fmt.Errorf("%w: %d overflows %s", value.ErrInvalidValue, n, v.Type())
+		}
+
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.OverflowUint(wf.n) {
+			return fmt.Errorf("%w: %d overflows %s", value.ErrInvalidValue, wf.n, v.Type())
+		}
+
+		v.SetUint(wf.n)
+	case reflect.Float32:
+		v.SetFloat(float64(math.Float32frombits(uint32(wf.n))))
+	case reflect.Float64:
+		v.SetFloat(math.Float64frombits(wf.n))
+	case reflect.String:
+		v.SetString(string(wf.b))
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("%w: %s", ErrUnsupported, v.Type())
+		}
+
+		v.SetBytes(append([]byte(nil), wf.b...))
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupported, v.Type())
+	}
+
+	return nil
+}
+
+func wireTypeError(wf field) error {
+	return fmt.Errorf("%w: unexpected wire type %d in field %d", ErrInvalidWire, wf.typ, wf.num)
+}
+
+func container(v reflect.Value) (value.Container, bool) {
+	if !v.CanAddr() || !reflect.PointerTo(v.Type()).Implements(containerType) {
+		return nil, false
+	}
+
+	return v.Addr().Interface().(value.Container), true
+}
+
+func isNullable(c value.Container) bool {
+	_, ok := c.(interface{ IsNull() bool })
+
+	return ok
+}
+
+// isMessage reports whether the type is encoded as a nested message.
+func isMessage(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct &&
+		!reflect.PointerTo(t).Implements(containerType) &&
+		!t.Implements(textMarshalerType) &&
+		!reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+func isPackable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return !reflect.PointerTo(t).Implements(textUnmarshalerType)
+	default:
+		return false
+	}
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t
+}
@@ -0,0 +1,169 @@
+package proto_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alextanhongpin/value"
+	"github.com/alextanhongpin/value/proto"
+	"github.com/alextanhongpin/value/rules"
+)
+
+type address struct {
+	City   *value.Value[string] `proto:"1"`
+	Floors []int                `proto:"2"`
+}
+
+func (a *address) Validate() error {
+	return value.ValidateStruct(a)
+}
+
+type userDto struct {
+	Name      *value.Value[string]    `proto:"1"`
+	Age       *value.Value[int]       `proto:"2"`
+	Score     *value.Value[float64]   `proto:"3"`
+	Active    *value.Value[bool]      `proto:"4"`
+	Address   *value.Object[*address] `proto:"5"`
+	Tags      []string                `proto:"6"`
+	CreatedAt *value.Value[time.Time] `proto:"7"`
+	Note      string                  `proto:"8"`
+	Skipped   string
+}
+
+func TestWrapper(t *testing.T) {
+	t.Parallel()
+
+	b, err := proto.Marshal(userDto{Age: value.New(150), Active: value.New(false)})
+	if err != nil {
+		t.Fatalf("failed to marshal: %s", err)
+	}
+
+	// Field 2 holds Int64Value{value: 150}, and field 4 an empty BoolValue,
+	// since false is the zero value.
+	want := []byte{0x12, 0x03, 0x08, 0x96, 0x01, 0x22, 0x00}
+	if !bytes.Equal(want, b) {
+		t.Fatalf("expected % x, got % x", want, b)
+	}
+
+	var dto userDto
+	if err := proto.Unmarshal(b, &dto); err != nil {
+		t.Fatalf("failed to unmarshal: %s", err)
+	}
+
+	if dto.Age.MustGet() != 150 {
+		t.Fatalf("expected 150, got %s", dto.Age)
+	}
+
+	if active, ok := dto.Active.Get(); !ok || active {
+		t.Fatalf("expected active to be set to false, got %s", dto.Active)
+	}
+
+	if dto.Name != nil || dto.Address != nil {
+		t.Fatalf("expected absent fields to be not set, got %s, %v", dto.Name, dto.Address)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	in := userDto{
+		Name:      value.New("john"),
+		Age:       value.New(-1),
+		Score:     value.New(0.5),
+		Address:   value.NewObject(&address{City: value.New("Singapore"), Floors: []int{1, 300, -2}}),
+		Tags:      []string{"a", ""},
+		CreatedAt: value.New(createdAt),
+		Note:      "note",
+		Skipped:   "skipped",
+	}
+
+	b, err := proto.Marshal(&in)
+	if err != nil {
+		t.Fatalf("failed to marshal: %s", err)
+	}
+
+	var out userDto
+	if err := proto.Unmarshal(b, &out); err != nil {
+		t.Fatalf("failed to unmarshal: %s", err)
+	}
+
+	if out.Name.MustGet() != "john" || out.Age.MustGet() != -1 || out.Score.MustGet() != 0.5 {
+		t.Fatalf("unexpected values: %s, %s, %s", out.Name, out.Age, out.Score)
+	}
+
+	if !out.Active.IsZero() {
+		t.Fatalf("expected active to be not set, got %s", out.Active)
+	}
+
+	addr := out.Address.MustGet()
+	if addr.City.MustGet() != "Singapore" || len(addr.Floors) != 3 || addr.Floors[1] != 300 || addr.Floors[2] != -2 {
+		t.Fatalf("unexpected address: %s, %v", addr.City, addr.Floors)
+	}
+
+	if len(out.Tags) != 2 || out.Tags[1] != "" || !out.CreatedAt.MustGet().Equal(createdAt) || out.Note != "note" || out.Skipped != "" {
+		t.Fatalf("unexpected fields: %+v", out)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	t.Parallel()
+
+	b, err := proto.Marshal(userDto{Name: value.New("johnny")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dto := userDto{Name: value.New("", value.Rules(rules.MaxLen[string](4)))}
+	if err := proto.Unmarshal(b, &dto); !errors.Is(err, value.ErrInvalidValue) {
+		t.Fatalf("expected %s, got %v", value.ErrInvalidValue, err)
+	}
+
+	if err := proto.Unmarshal([]byte{0x0a, 0x05}, &dto); !errors.Is(err, proto.ErrInvalidWire) {
+		t.Fatalf("expected %s, got %v", proto.ErrInvalidWire, err)
+	}
+
+	type nullable struct {
+		Note value.Nullable[string] `proto:"1"`
+	}
+
+	if _, err := proto.Marshal(nullable{Note: value.NewNullable("x")}); !errors.Is(err, proto.ErrUnsupported) {
+		t.Fatalf("expected %s, got %v", proto.ErrUnsupported, err)
+	}
+}
+
+func TestFieldNumbers(t *testing.T) {
+	t.Parallel()
+
+	type largest struct {
+		Name *value.Value[string] `proto:"536870911"`
+	}
+
+	if _, err := proto.Marshal(largest{Name: value.New("john")}); err != nil {
+		t.Fatalf("expected valid field number, got %s", err)
+	}
+
+	type tooLarge struct {
+		Name *value.Value[string] `proto:"536870912"`
+	}
+
+	type reservedFirst struct {
+		Name *value.Value[string] `proto:"19000"`
+	}
+
+	type reservedLast struct {
+		Name *value.Value[string] `proto:"19999"`
+	}
+
+	type zero struct {
+		Name *value.Value[string] `proto:"0"`
+	}
+
+	for _, v := range []any{tooLarge{}, reservedFirst{}, reservedLast{}, zero{}} {
+		if _, err := proto.Marshal(v); err == nil {
+			t.Fatalf("expected invalid field number on %T, got nil", v)
+		}
+	}
+}
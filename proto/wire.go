@@ -0,0 +1,126 @@
+package proto
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidWire = errors.New("proto: invalid wire format")
+
+// wireType is the encoding of a field on the wire.
+type wireType int
+
+const (
+	wireVarint  wireType = 0
+	wireFixed64 wireType = 1
+	wireBytes   wireType = 2
+	wireFixed32 wireType = 5
+)
+
+// field is a field read from the wire. Varints and fixed numbers are held in
+// n, and length-delimited fields in b.
+type field struct {
+	num int
+	typ wireType
+	n   uint64
+	b   []byte
+}
+
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+
+	return append(b, byte(v))
+}
+
+func appendFixed32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+
+	return append(b, buf[:]...)
+}
+
+func appendFixed64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+
+	return append(b, buf[:]...)
+}
+
+func appendTag(b []byte, num int, typ wireType) []byte {
+	return appendVarint(b, uint64(num)<<3|uint64(typ))
+}
+
+func appendBytes(b []byte, num int, v []byte) []byte {
+	b = appendTag(b, num, wireBytes)
+	b = appendVarint(b, uint64(len(v)))
+
+	return append(b, v...)
+}
+
+// consumeField reads the next field, and returns the rest of the input.
+func consumeField(b []byte) (field, []byte, error) {
+	tag, n := binary.Uvarint(b)
+	if n <= 0 {
+		return field{}, nil, fmt.Errorf("%w: bad tag", ErrInvalidWire)
+	}
+	b = b[n:]
+
+	f := field{num: int(tag >> 3), typ: wireType(tag & 7)}
+	if f.num <= 0 {
+		return field{}, nil, fmt.Errorf("%w: bad field number %d", ErrInvalidWire, f.num)
+	}
+
+	switch f.typ {
+	case wireVarint, wireFixed64, wireFixed32:
+		var err error
+		f.n, b, err = consumeNumber(b, f.typ)
+		if err != nil {
+			return field{}, nil, fmt.Errorf("%w in field %d", err, f.num)
+		}
+
+		return f, b, nil
+	case wireBytes:
+		size, n := binary.Uvarint(b)
+		if n <= 0 || size > uint64(len(b)-n) {
+			return field{}, nil, fmt.Errorf("%w: bad length in field %d", ErrInvalidWire, f.num)
+		}
+
+		f.b = b[n : n+int(size)]
+
+		return f, b[n+int(size):], nil
+	default:
+		return field{}, nil, fmt.Errorf("%w: unsupported wire type %d in field %d", ErrInvalidWire, f.typ, f.num)
+	}
+}
+
+// consumeNumber reads a varint or a fixed number, and returns the rest of the
+// input.
+func consumeNumber(b []byte, typ wireType) (uint64, []byte, error) {
+	switch typ {
+	case wireVarint:
+		v, n := binary.Uvarint(b)
+		if n <= 0 {
+			return 0, nil, fmt.Errorf("%w: bad varint", ErrInvalidWire)
+		}
+
+		return v, b[n:], nil
+	case wireFixed64:
+		if len(b) < 8 {
+			return 0, nil, fmt.Errorf("%w: short fixed64", ErrInvalidWire)
+		}
+
+		return binary.LittleEndian.Uint64(b), b[8:], nil
+	case wireFixed32:
+		if len(b) < 4 {
+			return 0, nil, fmt.Errorf("%w: short fixed32", ErrInvalidWire)
+		}
+
+		return uint64(binary.LittleEndian.Uint32(b)), b[4:], nil
+	default:
+		return 0, nil, fmt.Errorf("%w: wire type %d is not a number", ErrInvalidWire, typ)
+	}
+}
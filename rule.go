@@ -0,0 +1,92 @@
+package value
+
+import "fmt"
+
+// Rule validates a value of type T.
+type Rule[T any] interface {
+	Validate(T) error
+}
+
+// RuleFunc adapts a function to a Rule.
+type RuleFunc[T any] func(T) error
+
+func (f RuleFunc[T]) Validate(t T) error {
+	return f(t)
+}
+
+// Option configures a Value.
+type Option[T any] func(*Value[T])
+
+// Rules attaches rules that are enforced by Set, Validate and UnmarshalJSON.
+//
+// Rules belong to the Value instance, so a nil *Value field that is allocated
+// by a decoder has no rules attached.
+func Rules[T any](rules ...Rule[T]) Option[T] {
+	return func(v *Value[T]) {
+		if v.rules == nil {
+			v.rules = new([]Rule[T])
+		}
+
+		*v.rules = append(*v.rules, rules...)
+	}
+}
+
+func (v *Value[T]) validate(t T) error {
+	if v.rules == nil {
+		return nil
+	}
+
+	for _, r := range *v.rules {
+		if err := r.Validate(t); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RuleError is the error returned by the rules package. It wraps
+// ErrInvalidValue and carries a machine-readable code and the rule parameters.
+type RuleError struct {
+	Code    string
+	Params  map[string]any
+	Message string
+}
+
+func (e *RuleError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrInvalidValue, e.Message)
+}
+
+func (e *RuleError) Unwrap() error {
+	return ErrInvalidValue
+}
+
+// Constraint describes a rule with a machine-readable code and the rule
+// parameters, like RuleError, so that the rules can be exported, e.g. to a
+// JSON Schema.
+type Constraint struct {
+	Code   string
+	Params map[string]any
+}
+
+// Constrainer is implemented by rules that describe their constraints.
+type Constrainer interface {
+	Constraints() []Constraint
+}
+
+// Constraints returns the constraints of the attached rules that implement
+// Constrainer, in order.
+func (v *Value[T]) Constraints() []Constraint {
+	if v == nil || v.rules == nil {
+		return nil
+	}
+
+	var cs []Constraint
+	for _, r := range *v.rules {
+		if c, ok := r.(Constrainer); ok {
+			cs = append(cs, c.Constraints()...)
+		}
+	}
+
+	return cs
+}
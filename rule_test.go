@@ -0,0 +1,58 @@
+package value_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/alextanhongpin/value"
+)
+
+var errAgeOutOfRange = errors.New("age out of range")
+
+func ageRange(n int) error {
+	if n < 0 || n > 150 {
+		return errAgeOutOfRange
+	}
+
+	return nil
+}
+
+func TestValueRules(t *testing.T) {
+	t.Parallel()
+
+	t.Run("set", func(t *testing.T) {
+		t.Parallel()
+
+		age := value.New(18, value.Rules[int](value.RuleFunc[int](ageRange)))
+		if err := age.Validate(); err != nil {
+			t.Fatalf("expected valid age, got %s", err)
+		}
+
+		if err := age.Set(-1); !errors.Is(err, errAgeOutOfRange) {
+			t.Fatalf("expected %s, got %v", errAgeOutOfRange, err)
+		}
+
+		if got := age.MustGet(); got != 18 {
+			t.Fatalf("expected 18, got %d", got)
+		}
+	})
+
+	t.Run("new", func(t *testing.T) {
+		t.Parallel()
+
+		age := value.New(200, value.Rules[int](value.RuleFunc[int](ageRange)))
+		if err := age.Validate(); !errors.Is(err, errAgeOutOfRange) {
+			t.Fatalf("expected %s, got %v", errAgeOutOfRange, err)
+		}
+	})
+
+	t.Run("unmarshal", func(t *testing.T) {
+		t.Parallel()
+
+		age := value.New(18, value.Rules[int](value.RuleFunc[int](ageRange)))
+		if err := json.Unmarshal([]byte(`200`), age); !errors.Is(err, errAgeOutOfRange) {
+			t.Fatalf("expected %s, got %v", errAgeOutOfRange, err)
+		}
+	})
+}
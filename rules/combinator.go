@@ -0,0 +1,93 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alextanhongpin/value"
+)
+
+type and[T any] []value.Rule[T]
+
+// And returns the first error from the rules, in order.
+func And[T any](rules ...value.Rule[T]) value.Rule[T] {
+	return and[T](rules)
+}
+
+func (a and[T]) Validate(t T) error {
+	for _, r := range a {
+		if err := r.Validate(t); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Constraints returns the constraints of the rules that describe themselves,
+// since all of them must hold.
+func (a and[T]) Constraints() []value.Constraint {
+	var cs []value.Constraint
+	for _, r := range a {
+		if c, ok := r.(value.Constrainer); ok {
+			cs = append(cs, c.Constraints()...)
+		}
+	}
+
+	return cs
+}
+
+type or[T any] []value.Rule[T]
+
+// Or passes if at least one of the rules passes.
+func Or[T any](rules ...value.Rule[T]) value.Rule[T] {
+	return or[T](rules)
+}
+
+func (o or[T]) Validate(t T) error {
+	errs := make([]error, 0, len(o))
+	msgs := make([]string, 0, len(o))
+	for _, r := range o {
+		err := r.Validate(t)
+		if err == nil {
+			return nil
+		}
+
+		errs = append(errs, err)
+		msgs = append(msgs, err.Error())
+	}
+
+	return &value.RuleError{
+		Code:    CodeOr,
+		Params:  map[string]any{"errors": errs},
+		Message: fmt.Sprintf("must satisfy one of (%s)", strings.Join(msgs, "; ")),
+	}
+}
+
+type not[T any] struct {
+	rule value.Rule[T]
+}
+
+// Not passes if the rule fails.
+func Not[T any](rule value.Rule[T]) value.Rule[T] {
+	return &not[T]{rule: rule}
+}
+
+func (n *not[T]) Validate(t T) error {
+	if err := n.rule.Validate(t); err != nil {
+		return nil
+	}
+
+	return &value.RuleError{
+		Code:    CodeNot,
+		Message: fmt.Sprintf("must not satisfy %s", describe(n.rule)),
+	}
+}
+
+func describe[T any](r value.Rule[T]) string {
+	if r, ok := r.(*rule[T]); ok {
+		return r.code
+	}
+
+	return "rule"
+}
@@ -0,0 +1,252 @@
+// Package rules provides reusable rules for value.Value.
+//
+// Every rule returns a *value.RuleError that wraps value.ErrInvalidValue.
+package rules
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/alextanhongpin/value"
+)
+
+// Ordered mirrors golang.org/x/exp/constraints.Ordered.
+type Ordered interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 |
+		~string
+}
+
+const (
+	CodeMin      = "min"
+	CodeMax      = "max"
+	CodeBetween  = "between"
+	CodeLen      = "len"
+	CodeMinLen   = "min_len"
+	CodeMaxLen   = "max_len"
+	CodeRegex    = "regex"
+	CodeOneOf    = value.CodeOneOf
+	CodeNotEmpty = "not_empty"
+	CodePrefix   = "prefix"
+	CodeSuffix   = "suffix"
+	CodeUTF8     = "utf8"
+	CodeOr       = "or"
+	CodeNot      = "not"
+)
+
+type rule[T any] struct {
+	code    string
+	params  map[string]any
+	message string
+	valid   func(T) bool
+}
+
+func (r *rule[T]) Validate(t T) error {
+	if r.valid(t) {
+		return nil
+	}
+
+	return r.error()
+}
+
+func (r *rule[T]) Constraints() []value.Constraint {
+	return []value.Constraint{{Code: r.code, Params: r.params}}
+}
+
+func (r *rule[T]) error() *value.RuleError {
+	return &value.RuleError{
+		Code:    r.code,
+		Params:  r.params,
+		Message: r.message,
+	}
+}
+
+func Min[T Ordered](min T) value.Rule[T] {
+	return &rule[T]{
+		code:    CodeMin,
+		params:  map[string]any{"min": min},
+		message: fmt.Sprintf("must be at least %v", min),
+		valid: func(t T) bool {
+			return t >= min
+		},
+	}
+}
+
+func Max[T Ordered](max T) value.Rule[T] {
+	return &rule[T]{
+		code:    CodeMax,
+		params:  map[string]any{"max": max},
+		message: fmt.Sprintf("must be at most %v", max),
+		valid: func(t T) bool {
+			return t <= max
+		},
+	}
+}
+
+// Between checks that the value is within min and max, inclusive.
+func Between[T Ordered](min, max T) value.Rule[T] {
+	return &rule[T]{
+		code:    CodeBetween,
+		params:  map[string]any{"min": min, "max": max},
+		message: fmt.Sprintf("must be between %v and %v", min, max),
+		valid: func(t T) bool {
+			return t >= min && t <= max
+		},
+	}
+}
+
+// Len checks the length of a string, slice, array or map. Strings are
+// measured in runes.
+func Len[T any](n int) value.Rule[T] {
+	length := lengthOf[T]("Len")
+
+	return &rule[T]{
+		code:    CodeLen,
+		params:  map[string]any{"len": n},
+		message: fmt.Sprintf("must have length %d", n),
+		valid: func(t T) bool {
+			return length(t) == n
+		},
+	}
+}
+
+func MinLen[T any](min int) value.Rule[T] {
+	length := lengthOf[T]("MinLen")
+
+	return &rule[T]{
+		code:    CodeMinLen,
+		params:  map[string]any{"min": min},
+		message: fmt.Sprintf("must have length of at least %d", min),
+		valid: func(t T) bool {
+			return length(t) >= min
+		},
+	}
+}
+
+func MaxLen[T any](max int) value.Rule[T] {
+	length := lengthOf[T]("MaxLen")
+
+	return &rule[T]{
+		code:    CodeMaxLen,
+		params:  map[string]any{"max": max},
+		message: fmt.Sprintf("must have length of at most %d", max),
+		valid: func(t T) bool {
+			return length(t) <= max
+		},
+	}
+}
+
+// Regex checks that the value matches the pattern. It panics if the pattern
+// does not compile.
+func Regex[T ~string](pattern string) value.Rule[T] {
+	re := regexp.MustCompile(pattern)
+
+	return &rule[T]{
+		code:    CodeRegex,
+		params:  map[string]any{"pattern": pattern},
+		message: fmt.Sprintf("must match pattern %q", pattern),
+		valid: func(t T) bool {
+			return re.MatchString(string(t))
+		},
+	}
+}
+
+func OneOf[T comparable](values ...T) value.Rule[T] {
+	names := make([]string, len(values))
+	for i, v := range values {
+		names[i] = fmt.Sprint(v)
+	}
+
+	return &rule[T]{
+		code:    CodeOneOf,
+		params:  map[string]any{"values": values},
+		message: fmt.Sprintf("must be one of %s", strings.Join(names, ", ")),
+		valid: func(t T) bool {
+			for _, v := range values {
+				if v == t {
+					return true
+				}
+			}
+
+			return false
+		},
+	}
+}
+
+// NotEmpty checks that the value is not the zero value. Strings, slices and
+// maps must have at least one element.
+func NotEmpty[T any]() value.Rule[T] {
+	return &rule[T]{
+		code:    CodeNotEmpty,
+		message: "must not be empty",
+		valid: func(t T) bool {
+			v := reflect.ValueOf(t)
+			if !v.IsValid() {
+				return false
+			}
+
+			switch v.Kind() {
+			case reflect.String, reflect.Slice, reflect.Map:
+				return v.Len() > 0
+			default:
+				return !v.IsZero()
+			}
+		},
+	}
+}
+
+func Prefix[T ~string](prefix T) value.Rule[T] {
+	return &rule[T]{
+		code:    CodePrefix,
+		params:  map[string]any{"prefix": string(prefix)},
+		message: fmt.Sprintf("must start with %q", string(prefix)),
+		valid: func(t T) bool {
+			return strings.HasPrefix(string(t), string(prefix))
+		},
+	}
+}
+
+func Suffix[T ~string](suffix T) value.Rule[T] {
+	return &rule[T]{
+		code:    CodeSuffix,
+		params:  map[string]any{"suffix": string(suffix)},
+		message: fmt.Sprintf("must end with %q", string(suffix)),
+		valid: func(t T) bool {
+			return strings.HasSuffix(string(t), string(suffix))
+		},
+	}
+}
+
+// UTF8 checks that the value is a valid UTF-8 encoded string.
+func UTF8[T ~string]() value.Rule[T] {
+	return &rule[T]{
+		code:    CodeUTF8,
+		message: "must be valid UTF-8",
+		valid: func(t T) bool {
+			return utf8.ValidString(string(t))
+		},
+	}
+}
+
+// lengthOf returns a function that measures the length of T. It panics if T
+// has no length, so that misuse is caught when the rule is declared.
+func lengthOf[T any](name string) func(T) int {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+
+	switch typ.Kind() {
+	case reflect.String:
+		return func(t T) int {
+			return utf8.RuneCountInString(reflect.ValueOf(t).String())
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return func(t T) int {
+			return reflect.ValueOf(t).Len()
+		}
+	default:
+		panic(fmt.Sprintf("rules: %s requires a string, slice, array or map, got %s", name, typ))
+	}
+}
@@ -0,0 +1,105 @@
+package rules_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alextanhongpin/value"
+	"github.com/alextanhongpin/value/rules"
+)
+
+func TestRules(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		rule  value.Rule[string]
+		input string
+		code  string
+	}{
+		{"min", rules.Min("b"), "a", rules.CodeMin},
+		{"max", rules.Max("b"), "c", rules.CodeMax},
+		{"between", rules.Between("b", "d"), "e", rules.CodeBetween},
+		{"len", rules.Len[string](2), "abc", rules.CodeLen},
+		{"len runes", rules.Len[string](2), "日本", ""},
+		{"min len", rules.MinLen[string](2), "a", rules.CodeMinLen},
+		{"max len", rules.MaxLen[string](2), "abc", rules.CodeMaxLen},
+		{"regex", rules.Regex[string](`^\d+$`), "12a", rules.CodeRegex},
+		{"one of", rules.OneOf("mm", "cm", "m"), "km", rules.CodeOneOf},
+		{"not empty", rules.NotEmpty[string](), "", rules.CodeNotEmpty},
+		{"prefix", rules.Prefix("+65"), "+60123", rules.CodePrefix},
+		{"suffix", rules.Suffix(".com"), "mail.org", rules.CodeSuffix},
+		{"utf8", rules.UTF8[string](), "\xff", rules.CodeUTF8},
+		{"and", rules.And(rules.MinLen[string](1), rules.MaxLen[string](2)), "abc", rules.CodeMaxLen},
+		{"or", rules.Or(rules.Prefix("a"), rules.Prefix("b")), "c", rules.CodeOr},
+		{"or pass", rules.Or(rules.Prefix("a"), rules.Prefix("b")), "b", ""},
+		{"not", rules.Not(rules.Prefix("a")), "abc", rules.CodeNot},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.rule.Validate(tc.input)
+			if tc.code == "" {
+				if err != nil {
+					t.Fatalf("expected valid, got %s", err)
+				}
+
+				return
+			}
+
+			if !errors.Is(err, value.ErrInvalidValue) {
+				t.Fatalf("expected %s, got %v", value.ErrInvalidValue, err)
+			}
+
+			var ruleErr *value.RuleError
+			if !errors.As(err, &ruleErr) {
+				t.Fatalf("expected rule error, got %T", err)
+			}
+
+			if ruleErr.Code != tc.code {
+				t.Fatalf("expected code %s, got %s", tc.code, ruleErr.Code)
+			}
+		})
+	}
+}
+
+func TestValueWithRules(t *testing.T) {
+	t.Parallel()
+
+	age := value.New(18, value.Rules(rules.Min(0), rules.Max(150)))
+	if err := age.Validate(); err != nil {
+		t.Fatalf("expected valid age, got %s", err)
+	}
+
+	if err := age.Set(151); !errors.Is(err, value.ErrInvalidValue) {
+		t.Fatalf("expected %s, got %v", value.ErrInvalidValue, err)
+	}
+
+	tags := value.New([]string{"a"}, value.Rules(rules.MaxLen[[]string](1)))
+	if err := tags.Set([]string{"a", "b"}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestConstraints(t *testing.T) {
+	t.Parallel()
+
+	v := value.New("", value.Rules[string](
+		rules.And(rules.MinLen[string](1), rules.MaxLen[string](10)),
+		rules.Or(rules.Prefix("a"), rules.Prefix("b")),
+		value.RuleFunc[string](func(string) error { return nil }),
+	))
+
+	cs := v.Constraints()
+	if len(cs) != 2 || cs[0].Code != rules.CodeMinLen || cs[1].Code != rules.CodeMaxLen {
+		t.Fatalf("expected min_len and max_len, got %v", cs)
+	}
+
+	if max := cs[1].Params["max"]; max != 10 {
+		t.Fatalf("expected 10, got %v", max)
+	}
+}
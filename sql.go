@@ -0,0 +1,155 @@
+package value
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"reflect"
+)
+
+// Scan implements sql.Scanner. NULL resets the value to not set. The rules
+// attached to the value are enforced.
+func (v *Value[T]) Scan(src any) error {
+	if src == nil {
+		v.reset()
+
+		return nil
+	}
+
+	t, err := scan[T](src)
+	if err != nil {
+		return err
+	}
+
+	return v.Set(t)
+}
+
+// Value implements driver.Valuer. Values that are not set are stored as NULL.
+func (v Value[T]) Value() (driver.Value, error) {
+	if v.IsZero() {
+		return nil, nil
+	}
+
+	return driverValue(v.value)
+}
+
+func (v *Value[T]) reset() {
+	var t T
+	v.value = t
+	v.dirty = false
+}
+
+func (o *Object[T]) reset() {
+	var t T
+	o.value = t
+	o.dirty = false
+}
+
+// Scan implements sql.Scanner. NULL resets the object to not set.
+//
+// Like UnmarshalJSON, the scanned object is not validated. Wrap the object
+// with Strict to validate it on Scan.
+func (o *Object[T]) Scan(src any) error {
+	if src == nil {
+		o.reset()
+
+		return nil
+	}
+
+	t, err := scan[T](src)
+	if err != nil {
+		return err
+	}
+
+	o.value = t
+	o.dirty = true
+
+	return nil
+}
+
+// Value implements driver.Valuer. Objects that are not set are stored as
+// NULL.
+func (o *Object[T]) Value() (driver.Value, error) {
+	if o.IsZero() {
+		return nil, nil
+	}
+
+	return driverValue(o.value)
+}
+
+type strictScanner interface {
+	sql.Scanner
+	ValidateOptional() error
+}
+
+// Strict returns a scanner that validates the value after scanning. NULL is
+// accepted. An invalid value leaves dst unchanged.
+//
+//	row.Scan(value.Strict(&user.Age))
+func Strict(dst strictScanner) sql.Scanner {
+	return &strict{dst: dst}
+}
+
+type strict struct {
+	dst strictScanner
+}
+
+func (s *strict) Scan(src any) error {
+	// Like Object.Set, an invalid value is refused, so it is scanned into a
+	// copy that replaces dst once it is validated.
+	dst := reflect.ValueOf(s.dst).Elem()
+	tmp := reflect.New(dst.Type())
+	tmp.Elem().Set(dst)
+
+	sc := tmp.Interface().(strictScanner)
+	if err := sc.Scan(src); err != nil {
+		return err
+	}
+
+	if err := sc.ValidateOptional(); err != nil {
+		return err
+	}
+
+	dst.Set(tmp.Elem())
+
+	return nil
+}
+
+// scan delegates to T if it implements sql.Scanner, and converts basic types
+// otherwise.
+func scan[T any](src any) (T, error) {
+	t := Zero[T]()
+	if s, ok := scanner(&t); ok {
+		return t, s.Scan(src)
+	}
+
+	return t, assign(target(&t), src)
+}
+
+func scanner[T any](t *T) (sql.Scanner, bool) {
+	if s, ok := any(*t).(sql.Scanner); ok && reflect.ValueOf(t).Elem().Kind() == reflect.Pointer {
+		return s, true
+	}
+
+	s, ok := any(t).(sql.Scanner)
+
+	return s, ok
+}
+
+func driverValue(v any) (driver.Value, error) {
+	dv, err := driver.DefaultParameterConverter.ConvertValue(v)
+	if err == nil {
+		return dv, nil
+	}
+
+	if m, ok := v.(encoding.TextMarshaler); ok {
+		b, err := m.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+
+		return string(b), nil
+	}
+
+	return nil, err
+}
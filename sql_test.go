@@ -0,0 +1,187 @@
+package value_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/alextanhongpin/value"
+	"github.com/alextanhongpin/value/rules"
+)
+
+// fakeDriver is an in-memory driver that stores the arguments of every Exec
+// as a row, and returns all the rows on Query.
+type fakeDriver struct {
+	mu   sync.Mutex
+	rows [][]driver.Value
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{d: d}, nil }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) { return &fakeStmt{d: c.d}, nil }
+func (c *fakeConn) Close() error                              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+type fakeStmt struct{ d *fakeDriver }
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+
+	s.d.rows = append(s.d.rows, args)
+
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+
+	return &fakeRows{rows: append([][]driver.Value(nil), s.d.rows...)}, nil
+}
+
+type fakeRows struct{ rows [][]driver.Value }
+
+func (r *fakeRows) Columns() []string { return []string{"name", "age"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+
+	return nil
+}
+
+func init() {
+	sql.Register("value-fake", &fakeDriver{})
+}
+
+type age int
+
+func (a *age) Validate() error {
+	if a == nil {
+		return value.ErrNotSet
+	}
+
+	if *a < 0 {
+		return value.ErrInvalidValue
+	}
+
+	return nil
+}
+
+func newAge(n int) *age {
+	a := age(n)
+	return &a
+}
+
+func TestSQL(t *testing.T) {
+	db, err := sql.Open("value-fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var unset *value.Object[*age]
+	inserts := [][]any{
+		{value.New("john"), value.NewObject(newAge(10))},
+		{&value.Value[string]{}, unset},
+		{value.New("jane"), value.NewObject(newAge(-1))},
+	}
+	for _, args := range inserts {
+		if _, err := db.Exec("insert", args...); err != nil {
+			t.Fatalf("failed to insert: %s", err)
+		}
+	}
+
+	rows, err := db.Query("select")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	type row struct {
+		name *value.Value[string]
+		age  *value.Object[*age]
+	}
+
+	var got []row
+	for rows.Next() {
+		r := row{
+			name: value.New("", value.Rules(rules.MaxLen[string](4))),
+			age:  &value.Object[*age]{},
+		}
+		if err := rows.Scan(r.name, r.age); err != nil {
+			t.Fatalf("failed to scan: %s", err)
+		}
+
+		got = append(got, r)
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(got))
+	}
+
+	if name := got[0].name.MustGet(); name != "john" {
+		t.Fatalf("expected john, got %s", name)
+	}
+
+	if a := got[0].age.MustGet(); *a != 10 {
+		t.Fatalf("expected 10, got %d", *a)
+	}
+
+	if !got[1].name.IsZero() || !got[1].age.IsZero() {
+		t.Fatalf("expected NULL to be not set, got %s, %v", got[1].name, got[1].age)
+	}
+
+	// Lenient by default.
+	if got[2].age.Valid() {
+		t.Fatal("expected invalid age to be scanned")
+	}
+
+	t.Run("strict", func(t *testing.T) {
+		var a value.Object[*age]
+		if err := value.Strict(&a).Scan(int64(-1)); !errors.Is(err, value.ErrInvalidValue) {
+			t.Fatalf("expected %s, got %v", value.ErrInvalidValue, err)
+		}
+
+		if !a.IsZero() {
+			t.Fatalf("expected invalid age to be refused, got %v", a.MustGet())
+		}
+
+		if err := value.Strict(&a).Scan(int64(10)); err != nil {
+			t.Fatalf("failed to scan: %s", err)
+		}
+
+		if err := value.Strict(&a).Scan(int64(-1)); err == nil {
+			t.Fatal("expected invalid age to be refused")
+		}
+
+		if got := a.MustGet(); *got != 10 {
+			t.Fatalf("expected 10, got %d", *got)
+		}
+
+		if err := value.Strict(&a).Scan(nil); err != nil {
+			t.Fatalf("expected NULL to be accepted, got %s", err)
+		}
+	})
+
+	t.Run("rules", func(t *testing.T) {
+		name := value.New("", value.Rules(rules.MaxLen[string](4)))
+		if err := name.Scan("johnny"); !errors.Is(err, value.ErrInvalidValue) {
+			t.Fatalf("expected %s, got %v", value.ErrInvalidValue, err)
+		}
+	})
+}
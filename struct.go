@@ -0,0 +1,474 @@
+package value
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var ErrNotStruct = errors.New("not a struct")
+
+// ErrorMap maps field paths to their errors. A path with several errors maps
+// to an Errors that holds all of them.
+type ErrorMap map[string]error
+
+func (m ErrorMap) Error() string {
+	errs := m.Unwrap()
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the errors sorted by path.
+func (m ErrorMap) Unwrap() []error {
+	paths := make([]string, 0, len(m))
+	for path := range m {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	errs := make([]error, len(paths))
+	for i, path := range paths {
+		errs[i] = m[path]
+	}
+
+	return errs
+}
+
+func (m ErrorMap) Is(target error) bool {
+	return Errors(m.Unwrap()).Is(target)
+}
+
+func (m ErrorMap) As(target any) bool {
+	return Errors(m.Unwrap()).As(target)
+}
+
+func (m ErrorMap) add(err error) {
+	switch e := err.(type) {
+	case nil:
+	case Errors:
+		for _, err := range e {
+			m.add(err)
+		}
+	case ErrorMap:
+		for _, err := range e {
+			m.add(err)
+		}
+	case *FieldError:
+		switch prev := m[e.Path].(type) {
+		case nil:
+			m[e.Path] = e
+		case Errors:
+			m[e.Path] = append(prev[:len(prev):len(prev)], e)
+		default:
+			m[e.Path] = Errors{prev, e}
+		}
+	default:
+		m.add(&FieldError{Code: codeOf(err), Err: err})
+	}
+}
+
+// ValidateStruct walks the exported fields of the struct recursively and
+// validates every Value, Object, Nullable and other validatable it finds.
+// Fields are named after their json tags. The errors are returned as an
+// ErrorMap keyed by the field path, with *FieldError values, or Errors of them
+// when a field has several errors.
+//
+// Fields are required by default. The `value` struct tag declares otherwise:
+//
+//	Name  *value.Value[string]      `json:"name" value:"required"`
+//	Color *value.Object[*RGB]       `json:"color" value:"optional"`
+//	Bio   *value.Value[string]      `json:"bio" value:"omitempty"`
+//
+// Optional fields may be unset, but must be valid when set. Omitempty fields
+// are also skipped when set to the zero value.
+//
+// Validatable fields are not walked further, since their Validate method is
+// expected to validate their own fields.
+func ValidateStruct(v any) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return ErrNotSet
+		}
+
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %T", ErrNotStruct, v)
+	}
+
+	w := &walker{errs: make(ErrorMap)}
+	if ptr := reflect.ValueOf(v); ptr.Kind() == reflect.Pointer {
+		w.enter(ptr)
+	}
+	w.walkStruct(addressable(rv), "")
+
+	return w.err()
+}
+
+// validateDecoded validates the destination of a decoder like ValidateStruct,
+// so that fields are required unless their `value` tag says otherwise.
+// Destinations that are not structs are validated like the elements of a
+// slice.
+func validateDecoded(v any) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return nil
+	}
+
+	w := &walker{errs: make(ErrorMap)}
+	if ptr := reflect.ValueOf(v); ptr.Kind() == reflect.Pointer {
+		w.enter(ptr)
+	}
+	if rv.Kind() == reflect.Struct && !reflect.PointerTo(rv.Type()).Implements(optionalValidatableType) {
+		// Like ValidateStruct, the fields of the destination are walked.
+		w.walkStruct(addressable(rv), "")
+	} else {
+		w.walkValue(rv, "", required)
+	}
+
+	return w.err()
+}
+
+var (
+	validatableType         = reflect.TypeOf((*validatable)(nil)).Elem()
+	optionalValidatableType = reflect.TypeOf((*optionalValidatable)(nil)).Elem()
+)
+
+// presence is set with the `value` struct tag, and controls whether a field
+// may be left unset.
+type presence int
+
+const (
+	// required fields must be set and valid. This is the default.
+	required presence = iota
+	// optional fields may be unset, but must be valid when set.
+	optional
+	// omitEmpty fields are like optional fields, but are also skipped when
+	// they are set to the zero value, e.g. an empty string.
+	omitEmpty
+)
+
+func parsePresence(tag string) presence {
+	for _, opt := range strings.Split(tag, ",") {
+		switch strings.TrimSpace(opt) {
+		case "required":
+			return required
+		case "optional":
+			return optional
+		case "omitempty":
+			return omitEmpty
+		}
+	}
+
+	return required
+}
+
+type optionalValidatable interface {
+	ValidateOptional() error
+}
+
+type zeroer interface {
+	IsZero() bool
+}
+
+// structPlan lists the fields of a struct type that need to be walked.
+type structPlan struct {
+	fields []fieldPlan
+}
+
+type fieldPlan struct {
+	index    []int
+	name     string
+	presence presence
+	// embedded fields share the path of their parent.
+	embedded bool
+}
+
+var structPlans sync.Map // map[reflect.Type]*structPlan
+
+func planStruct(t reflect.Type) *structPlan {
+	if p, ok := structPlans.Load(t); ok {
+		return p.(*structPlan)
+	}
+
+	p := &structPlan{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !promoted(f) {
+			continue
+		}
+
+		name, ok := fieldName(f)
+		if !ok {
+			continue
+		}
+
+		if !walkable(f.Type, make(map[reflect.Type]bool)) {
+			continue
+		}
+
+		embedded := f.Anonymous && name == ""
+		if embedded && isValidatable(f.Type) {
+			name, embedded = f.Name, false
+		}
+
+		p.fields = append(p.fields, fieldPlan{
+			index:    f.Index,
+			name:     name,
+			presence: parsePresence(f.Tag.Get("value")),
+			embedded: embedded,
+		})
+	}
+
+	actual, _ := structPlans.LoadOrStore(t, p)
+
+	return actual.(*structPlan)
+}
+
+// promoted reports whether the fields of an unexported embedded struct are
+// promoted, as encoding/json does.
+func promoted(f reflect.StructField) bool {
+	t := f.Type
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return f.Anonymous && t.Kind() == reflect.Struct && !isValidatable(f.Type)
+}
+
+// fieldName returns the json name of the field. Embedded fields without a
+// json name return an empty name.
+func fieldName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+	if name != "" {
+		return name, true
+	}
+
+	if f.Anonymous {
+		return "", true
+	}
+
+	return f.Name, true
+}
+
+// walkable reports whether values of the type may hold something to validate.
+func walkable(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if isValidatable(t) {
+		return true
+	}
+
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return walkable(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if (f.IsExported() || promoted(f)) && walkable(f.Type, seen) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func isValidatable(t reflect.Type) bool {
+	return t.Implements(validatableType) ||
+		(t.Kind() != reflect.Interface && reflect.PointerTo(t).Implements(validatableType))
+}
+
+// walker walks the fields of a struct, and collects the errors by path.
+type walker struct {
+	errs ErrorMap
+	// seen holds the pointers, maps and slices being walked, so that cycles
+	// are walked once, like encoding/json stops at them.
+	seen map[visit]bool
+}
+
+// visit identifies a pointer, map or slice by its address and type. Slices
+// also need their length, since they may share the address of their first
+// element.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// enter marks the value as being walked, and reports false if it already is.
+func (w *walker) enter(v reflect.Value) (visit, bool) {
+	key := visit{ptr: v.Pointer(), typ: v.Type()}
+	if v.Kind() == reflect.Slice {
+		key.len = v.Len()
+	}
+
+	if w.seen[key] {
+		return key, false
+	}
+
+	if w.seen == nil {
+		w.seen = make(map[visit]bool)
+	}
+	w.seen[key] = true
+
+	return key, true
+}
+
+func (w *walker) err() error {
+	if len(w.errs) == 0 {
+		return nil
+	}
+
+	return w.errs
+}
+
+func (w *walker) walkStruct(v reflect.Value, path string) {
+	for _, f := range planStruct(v.Type()).fields {
+		fv := v.FieldByIndex(f.index)
+		if f.embedded {
+			w.walkValue(fv, path, f.presence)
+			continue
+		}
+
+		w.walkValue(fv, joinPath(path, f.name), f.presence)
+	}
+}
+
+func (w *walker) walkValue(v reflect.Value, path string, p presence) {
+	if isValidatable(v.Type()) {
+		w.validateValue(v, path, p)
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return
+		}
+
+		key, ok := w.enter(v)
+		if !ok {
+			return
+		}
+		defer delete(w.seen, key)
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			w.walkValue(v.Elem(), path, p)
+		}
+	case reflect.Struct:
+		w.walkStruct(addressable(v), path)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			w.walkValue(v.Index(i), path+"["+strconv.Itoa(i)+"]", required)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			w.walkValue(addressable(iter.Value()), fmt.Sprintf("%s[%v]", path, iter.Key()), required)
+		}
+	}
+}
+
+func (w *walker) validateValue(v reflect.Value, path string, p presence) {
+	if v.Kind() == reflect.Interface && v.IsNil() {
+		return
+	}
+
+	val, ok := asValidatable(v)
+	if !ok {
+		return
+	}
+
+	switch p {
+	case omitEmpty:
+		if isEmpty(v) {
+			return
+		}
+
+		fallthrough
+	case optional:
+		if isZero(v, val) {
+			return
+		}
+
+		if o, ok := val.(optionalValidatable); ok {
+			w.errs.add(withPath(path, o.ValidateOptional()))
+			return
+		}
+	}
+
+	w.errs.add(withPath(path, val.Validate()))
+}
+
+// isZero reports whether the field is unset.
+func isZero(v reflect.Value, val validatable) bool {
+	if z, ok := val.(zeroer); ok {
+		return z.IsZero()
+	}
+
+	return (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil()
+}
+
+// isEmpty reports whether the field is unset, or holds the zero value. The
+// value held by containers is read with their Get method.
+func isEmpty(v reflect.Value) bool {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return true
+		}
+
+		v = v.Elem()
+	}
+
+	if get := addressable(v).Addr().MethodByName("Get"); get.IsValid() && get.Type().NumIn() == 0 && get.Type().NumOut() == 2 {
+		out := get.Call(nil)
+		return isEmpty(out[0])
+	}
+
+	return v.IsZero()
+}
+
+func asValidatable(v reflect.Value) (validatable, bool) {
+	if v.Kind() != reflect.Pointer && v.Kind() != reflect.Interface {
+		v = addressable(v).Addr()
+	}
+
+	val, ok := v.Interface().(validatable)
+
+	return val, ok
+}
+
+// addressable returns an addressable copy of the value if it is not
+// addressable, so that pointer receiver methods can be called.
+func addressable(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v
+	}
+
+	cp := reflect.New(v.Type()).Elem()
+	cp.Set(v)
+
+	return cp
+}
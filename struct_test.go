@@ -0,0 +1,202 @@
+package value_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alextanhongpin/value"
+	"github.com/alextanhongpin/value/rules"
+)
+
+type sku string
+
+func (s *sku) Validate() error {
+	if s == nil || *s == "" {
+		return value.ErrNotSet
+	}
+
+	return nil
+}
+
+type lineItem struct {
+	SKU      *value.Object[*sku] `json:"sku"`
+	Quantity *value.Value[int]   `json:"quantity"`
+}
+
+type audit struct {
+	CreatedBy *value.Value[string] `json:"createdBy"`
+}
+
+type order struct {
+	audit
+	ID      *value.Value[string] `json:"id"`
+	Items   []lineItem           `json:"items"`
+	Ignored *value.Value[string] `json:"-"`
+	Note    string               `json:"note"`
+}
+
+func TestValidateStruct(t *testing.T) {
+	t.Parallel()
+
+	o := order{
+		ID: value.New("order-1"),
+		Items: []lineItem{
+			{SKU: value.NewObject(newSKU("a")), Quantity: value.New(1)},
+			{SKU: value.NewObject(newSKU("")), Quantity: value.New(0, value.Rules(rules.Min(1)))},
+		},
+	}
+
+	err := value.ValidateStruct(&o)
+
+	var errs value.ErrorMap
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected error map, got %v", err)
+	}
+
+	want := map[string]string{
+		"createdBy":         value.CodeNotSet,
+		"items[1].sku":      value.CodeNotSet,
+		"items[1].quantity": rules.CodeMin,
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("expected %d errors, got %s", len(want), err)
+	}
+
+	for path, code := range want {
+		var fieldErr *value.FieldError
+		if !errors.As(errs[path], &fieldErr) {
+			t.Fatalf("expected field error at %s, got %v", path, errs[path])
+		}
+
+		if fieldErr.Code != code {
+			t.Fatalf("expected code %s at %s, got %s", code, path, fieldErr.Code)
+		}
+	}
+
+	if !errors.Is(err, value.ErrInvalidValue) {
+		t.Fatalf("expected %s, got %s", value.ErrInvalidValue, err)
+	}
+
+	o.CreatedBy = value.New("john")
+	o.Items = o.Items[:1]
+	if err := value.ValidateStruct(o); err != nil {
+		t.Fatalf("expected valid, got %s", err)
+	}
+}
+
+func newSKU(s string) *sku {
+	v := sku(s)
+	return &v
+}
+
+type profile struct {
+	Name     *value.Value[string]   `json:"name" value:"required"`
+	Nickname *value.Value[string]   `json:"nickname" value:"optional"`
+	Bio      *value.Value[string]   `json:"bio" value:"omitempty"`
+	Website  value.Nullable[string] `json:"website" value:"optional"`
+	Tag      *value.Object[*sku]    `json:"tag" value:"optional"`
+}
+
+func TestValidateStructPresence(t *testing.T) {
+	t.Parallel()
+
+	minLen := value.Rules(rules.MinLen[string](3))
+
+	tests := []struct {
+		name    string
+		profile profile
+		paths   []string
+	}{
+		{"required", profile{}, []string{"name"}},
+		{"optional unset", profile{Name: value.New("john"), Website: value.Null[string]()}, nil},
+		{"optional invalid", profile{Name: value.New("john"), Nickname: value.New("j", minLen)}, []string{"nickname"}},
+		{"optional object", profile{Name: value.New("john"), Tag: value.NewObject(newSKU(""))}, []string{"tag"}},
+		{"omitempty zero", profile{Name: value.New("john"), Bio: value.New("", minLen)}, nil},
+		{"omitempty invalid", profile{Name: value.New("john"), Bio: value.New("hi", minLen)}, []string{"bio"}},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := value.ValidateStruct(&tc.profile)
+			if len(tc.paths) == 0 {
+				if err != nil {
+					t.Fatalf("expected valid, got %s", err)
+				}
+
+				return
+			}
+
+			var errs value.ErrorMap
+			if !errors.As(err, &errs) {
+				t.Fatalf("expected error map, got %v", err)
+			}
+
+			if len(errs) != len(tc.paths) {
+				t.Fatalf("expected %v, got %s", tc.paths, err)
+			}
+
+			for _, path := range tc.paths {
+				if errs[path] == nil {
+					t.Fatalf("expected error at %s, got %s", path, err)
+				}
+			}
+		})
+	}
+}
+
+type twoErrors struct {
+	errs value.Errors
+}
+
+func (t *twoErrors) Validate() error {
+	return t.errs
+}
+
+func TestValidateStructSamePath(t *testing.T) {
+	t.Parallel()
+
+	errA := errors.New("a")
+	errB := errors.New("b")
+
+	v := struct {
+		P *twoErrors `json:"p"`
+	}{
+		P: &twoErrors{errs: value.Errors{errA, errB}},
+	}
+
+	err := value.ValidateStruct(&v)
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Fatalf("expected %s and %s, got %v", errA, errB, err)
+	}
+
+	if want := "p: a; p: b"; err.Error() != want {
+		t.Fatalf("expected %s, got %s", want, err)
+	}
+}
+
+type node struct {
+	Name *value.Value[string] `json:"name"`
+	Next *node                `json:"next"`
+}
+
+func TestValidateStructCycle(t *testing.T) {
+	t.Parallel()
+
+	n := &node{Name: value.New("", value.Rules(rules.NotEmpty[string]()))}
+	n.Next = n
+
+	err := value.ValidateStruct(n)
+
+	var errs value.ErrorMap
+	if !errors.As(err, &errs) || len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", err)
+	}
+
+	if errs["name"] == nil {
+		t.Fatalf("expected name error, got %v", errs)
+	}
+}
@@ -0,0 +1,102 @@
+package value
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// MarshalText implements encoding.TextMarshaler, so that Value can be used as
+// a JSON object key and as an XML attribute. T is encoded with its own MarshalText
+// method if it has one, and with strconv otherwise. Values that are not set
+// are encoded as empty text.
+func (v Value[T]) MarshalText() ([]byte, error) {
+	if v.IsZero() {
+		return []byte{}, nil
+	}
+
+	return MarshalText(v.value)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. The rules attached to
+// the value are enforced.
+func (v *Value[T]) UnmarshalText(text []byte) error {
+	t, err := unmarshalText[T](text)
+	if err != nil {
+		return err
+	}
+
+	return v.Set(t)
+}
+
+// MarshalText implements encoding.TextMarshaler. Objects that are not set are
+// encoded as empty text. Like MarshalJSON, it has a pointer receiver, so that
+// encoding/json does not prefer it for objects that are not addressable.
+func (o *Object[T]) MarshalText() ([]byte, error) {
+	if o.IsZero() {
+		return []byte{}, nil
+	}
+
+	return MarshalText(o.value)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Like UnmarshalJSON, the
+// object is not validated.
+func (o *Object[T]) UnmarshalText(text []byte) error {
+	t, err := unmarshalText[T](text)
+	if err != nil {
+		return err
+	}
+
+	o.value = t
+	o.dirty = true
+
+	return nil
+}
+
+// MarshalText marshals v with its MarshalText method if it has one, and with
+// strconv otherwise. Nil pointers are marshaled as empty text. It is shared
+// with the encoders of the subpackages, like ParseText.
+func MarshalText(v any) ([]byte, error) {
+	if m, ok := v.(encoding.TextMarshaler); ok {
+		return m.MarshalText()
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return []byte{}, nil
+		}
+
+		rv = rv.Elem()
+		if m, ok := rv.Interface().(encoding.TextMarshaler); ok {
+			return m.MarshalText()
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		return []byte(rv.String()), nil
+	case reflect.Bool:
+		return strconv.AppendBool(nil, rv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.AppendInt(nil, rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.AppendUint(nil, rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.AppendFloat(nil, rv.Float(), 'g', -1, rv.Type().Bits()), nil
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return append([]byte(nil), rv.Bytes()...), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: cannot marshal %T as text", ErrInvalidValue, v)
+}
+
+func unmarshalText[T any](text []byte) (T, error) {
+	t := Zero[T]()
+
+	return t, ParseText(target(&t), string(text))
+}
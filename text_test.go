@@ -0,0 +1,124 @@
+package value_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alextanhongpin/value"
+	"github.com/alextanhongpin/value/rules"
+)
+
+type currency string
+
+func (c currency) MarshalText() ([]byte, error) {
+	return []byte(strings.ToUpper(string(c))), nil
+}
+
+func (c *currency) UnmarshalText(text []byte) error {
+	*c = currency(strings.ToLower(string(text)))
+	return nil
+}
+
+func (c *currency) Validate() error {
+	if c == nil || *c == "" {
+		return value.ErrNotSet
+	}
+
+	return nil
+}
+
+type point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+func (p *point) Validate() error {
+	return nil
+}
+
+func TestText(t *testing.T) {
+	t.Parallel()
+
+	t.Run("object by value", func(t *testing.T) {
+		t.Parallel()
+
+		type shape struct {
+			Origin value.Object[*point] `json:"origin"`
+		}
+
+		s := shape{Origin: *value.NewObject(&point{X: 1, Y: 2})}
+
+		// Objects that are not addressable are not marshaled with their
+		// pointer methods, but must not fail as text.
+		for _, v := range []any{s, map[string]value.Object[*point]{"origin": s.Origin}} {
+			if _, err := json.Marshal(v); err != nil {
+				t.Fatalf("failed to marshal: %s", err)
+			}
+		}
+
+		b, err := json.Marshal(&s)
+		if err != nil {
+			t.Fatalf("failed to marshal: %s", err)
+		}
+
+		if want := `{"origin":{"x":1,"y":2}}`; string(b) != want {
+			t.Fatalf("expected %s, got %s", want, b)
+		}
+	})
+
+	t.Run("map key", func(t *testing.T) {
+		t.Parallel()
+
+		in := map[value.Value[int]]string{*value.New(1): "one", *value.New(2): "two"}
+		b, err := json.Marshal(in)
+		if err != nil {
+			t.Fatalf("failed to marshal: %s", err)
+		}
+
+		if want := `{"1":"one","2":"two"}`; string(b) != want {
+			t.Fatalf("expected %s, got %s", want, b)
+		}
+
+		var out map[value.Value[int]]string
+		if err := json.Unmarshal(b, &out); err != nil {
+			t.Fatalf("failed to unmarshal: %s", err)
+		}
+
+		if got := out[*value.New(2)]; got != "two" {
+			t.Fatalf("expected two, got %s", got)
+		}
+	})
+
+	t.Run("delegate", func(t *testing.T) {
+		t.Parallel()
+
+		var o value.Object[*currency]
+		if err := o.UnmarshalText([]byte("MYR")); err != nil {
+			t.Fatalf("failed to unmarshal: %s", err)
+		}
+
+		if got := *o.MustGet(); got != "myr" {
+			t.Fatalf("expected myr, got %s", got)
+		}
+
+		b, err := o.MarshalText()
+		if err != nil || string(b) != "MYR" {
+			t.Fatalf("expected MYR, got %s, %v", b, err)
+		}
+	})
+
+	t.Run("rules", func(t *testing.T) {
+		t.Parallel()
+
+		age := value.New(0, value.Rules(rules.Min(0)))
+		if err := age.UnmarshalText([]byte("-1")); !errors.Is(err, value.ErrInvalidValue) {
+			t.Fatalf("expected %s, got %v", value.ErrInvalidValue, err)
+		}
+
+		if err := age.UnmarshalText([]byte("abc")); err == nil {
+			t.Fatal("expected parse error, got nil")
+		}
+	})
+}
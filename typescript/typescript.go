@@ -0,0 +1,509 @@
+// Package typescript generates TypeScript interfaces and validator functions
+// for the DTOs, so that the rules attached to Value fields also run in the
+// web client.
+//
+//	doc := typescript.New()
+//	doc.Register("CreateUserDto", &CreateUserDto{
+//		Name: value.New("", value.Rules(rules.MaxLen[string](100))),
+//	})
+//	doc.WriteFile("dto.ts")
+//
+// The types are generated from the JSON Schema of the DTOs, so types that
+// implement jsonschema.Schemer, like the enums generated by valuegen, are
+// supported. Each validator returns the errors keyed by the field path, with
+// the same codes as the rules package, e.g. {"address.city": "not_set"}. The
+// codes are read from the jsonschema.CodesKeyword extension of the schemas.
+//
+// Regex patterns are copied as is, so they should be limited to the syntax
+// shared by RE2 and JavaScript.
+package typescript
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/alextanhongpin/value"
+	"github.com/alextanhongpin/value/jsonschema"
+)
+
+const header = `// Code generated by github.com/alextanhongpin/value/typescript. DO NOT EDIT.
+
+export type ValidationErrors = Record<string, string>;
+
+function join(path: string, key: string): string {
+  if (path === "") {
+    return key;
+  }
+
+  return key.startsWith("[") ? path + key : path + "." + key;
+}
+`
+
+const refPrefix = "#/$defs/"
+
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// ErrDuplicateName is returned when two types of the module have the same
+// name, e.g. when a name is registered twice.
+var ErrDuplicateName = errors.New("typescript: duplicate type name")
+
+// Document is a TypeScript module with the types and validators of the
+// registered DTOs.
+type Document struct {
+	dtos []dto
+}
+
+type dto struct {
+	name string
+	v    any
+}
+
+func New() *Document {
+	return &Document{}
+}
+
+// Register adds the DTO to the module under the name. Like
+// jsonschema.Generate, the DTO is a value, so that the rules attached to its
+// fields are included.
+func (d *Document) Register(name string, v any) {
+	d.dtos = append(d.dtos, dto{name: name, v: v})
+}
+
+// WriteTo writes the module. The registered DTOs are written first, followed
+// by the nested structs they refer to, sorted by name.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	g := jsonschema.NewGenerator()
+	g.RefPrefix = refPrefix
+
+	type decl struct {
+		name   string
+		schema map[string]any
+	}
+
+	// The nested structs of a registered type refer to its declaration. Other
+	// types that share the type name of a registered DTO are numbered, e.g.
+	// Address2, so that their references stay correct. Type names are
+	// exported, so both cases of the first letter are reserved.
+	registered := make(map[string]bool, len(d.dtos))
+	for _, dto := range d.dtos {
+		if dto.name != "" {
+			g.Reserve(typeName(dto.name), strings.ToLower(dto.name[:1])+dto.name[1:])
+		}
+
+		if registered[dto.name] {
+			return 0, fmt.Errorf("%w: %s", ErrDuplicateName, typeName(dto.name))
+		}
+		registered[dto.name] = true
+
+		if err := g.Define(dto.name, dto.v); err != nil {
+			return 0, err
+		}
+	}
+
+	defs := g.Defs()
+	decls := make([]decl, 0, len(defs))
+	for _, dto := range d.dtos {
+		decls = append(decls, decl{name: dto.name, schema: defs[dto.name].(map[string]any)})
+	}
+
+	names := make([]string, 0, len(defs))
+	for name := range defs {
+		if !registered[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		decls = append(decls, decl{name: name, schema: defs[name].(map[string]any)})
+	}
+
+	declared := make(map[string]bool, len(decls))
+	for _, decl := range decls {
+		name := typeName(decl.name)
+		if declared[name] {
+			return 0, fmt.Errorf("%w: %s", ErrDuplicateName, name)
+		}
+
+		declared[name] = true
+	}
+
+	var b strings.Builder
+	b.WriteString(header)
+	for _, decl := range decls {
+		b.WriteString("\n")
+		writeType(&b, typeName(decl.name), decl.schema)
+		b.WriteString("\n")
+		writeValidator(&b, typeName(decl.name), decl.schema)
+	}
+
+	n, err := io.WriteString(w, b.String())
+
+	return int64(n), err
+}
+
+func (d *Document) WriteFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := d.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func writeType(b *strings.Builder, name string, s map[string]any) {
+	if s["type"] == "object" && s["properties"] != nil {
+		fmt.Fprintf(b, "export interface %s %s\n", name, objectType(s, ""))
+		return
+	}
+
+	fmt.Fprintf(b, "export type %s = %s;\n", name, tsType(s, ""))
+}
+
+// tsType returns the TypeScript type of the schema.
+func tsType(s map[string]any, indent string) string {
+	if ref, ok := s["$ref"].(string); ok {
+		return typeName(strings.TrimPrefix(ref, refPrefix))
+	}
+
+	if anyOf, ok := s["anyOf"].([]any); ok {
+		types := make([]string, len(anyOf))
+		for i, sub := range anyOf {
+			types[i] = tsType(sub.(map[string]any), indent)
+		}
+
+		return strings.Join(types, " | ")
+	}
+
+	if enum, ok := s["enum"].([]any); ok {
+		types := make([]string, len(enum))
+		for i, v := range enum {
+			types[i] = literal(v)
+		}
+
+		return strings.Join(types, " | ")
+	}
+
+	var types []string
+	switch typ := s["type"].(type) {
+	case string:
+		types = []string{typ}
+	case []any:
+		for _, t := range typ {
+			types = append(types, t.(string))
+		}
+	default:
+		return "unknown"
+	}
+
+	for i, typ := range types {
+		switch typ {
+		case "integer", "number":
+			types[i] = "number"
+		case "object":
+			if _, ok := s["properties"]; ok {
+				types[i] = objectType(s, indent)
+			} else if additional, ok := s["additionalProperties"].(map[string]any); ok {
+				types[i] = "Record<string, " + tsType(additional, indent) + ">"
+			} else {
+				types[i] = "Record<string, unknown>"
+			}
+		case "array":
+			elem := "unknown"
+			if items, ok := s["items"].(map[string]any); ok {
+				elem = tsType(items, indent)
+			}
+
+			if strings.Contains(elem, " ") {
+				elem = "(" + elem + ")"
+			}
+
+			types[i] = elem + "[]"
+		}
+	}
+
+	return strings.Join(types, " | ")
+}
+
+func objectType(s map[string]any, indent string) string {
+	props, _ := s["properties"].(map[string]any)
+	required := requiredSet(s)
+
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, key := range sortedKeys(props) {
+		optional := "?"
+		if required[key] {
+			optional = ""
+		}
+
+		fmt.Fprintf(&b, "%s  %s%s: %s;\n", indent, propertyName(key), optional, tsType(props[key].(map[string]any), indent+"  "))
+	}
+	b.WriteString(indent + "}")
+
+	return b.String()
+}
+
+func writeValidator(b *strings.Builder, name string, s map[string]any) {
+	fmt.Fprintf(b, "export function validate%s(v: %s, path = \"\"): ValidationErrors {\n", name, name)
+	b.WriteString("  const errors: ValidationErrors = {};\n")
+
+	w := &validator{b: b}
+	w.check(s, "v", "path", "  ")
+
+	b.WriteString("  return errors;\n}\n")
+}
+
+// validator writes the checks of the schemas. Like the rules of a Value,
+// the first failing check of a field is reported.
+type validator struct {
+	b *strings.Builder
+	// depth numbers the loop variables of nested arrays.
+	depth int
+}
+
+func (w *validator) line(indent, format string, args ...any) {
+	w.b.WriteString(indent)
+	fmt.Fprintf(w.b, format, args...)
+	w.b.WriteString("\n")
+}
+
+// check writes the checks of the value at expr, whose path is the path
+// expression.
+func (w *validator) check(s map[string]any, expr, path, indent string) {
+	if ref, ok := s["$ref"].(string); ok {
+		w.line(indent, "Object.assign(errors, validate%s(%s, %s));", typeName(strings.TrimPrefix(ref, refPrefix)), expr, path)
+		return
+	}
+
+	if anyOf, ok := s["anyOf"].([]any); ok {
+		// Nullable schemas are checked after the null check of the
+		// property, so only the non-null schema is checked.
+		for _, sub := range anyOf {
+			if sub := sub.(map[string]any); sub["type"] != "null" {
+				w.check(sub, expr, path, indent)
+			}
+		}
+
+		return
+	}
+
+	conds := w.conditions(s, expr)
+	for i, c := range conds {
+		keyword := "} else if"
+		if i == 0 {
+			keyword = "if"
+		}
+
+		w.line(indent, "%s (%s) {", keyword, c[0])
+		w.line(indent, "  errors[%s] = %q;", path, c[1])
+	}
+
+	typ := nonNullType(s)
+	nested := typ == "object" && s["properties"] != nil || typ == "array" && s["items"] != nil
+	if !nested {
+		if len(conds) > 0 {
+			w.line(indent, "}")
+		}
+
+		return
+	}
+
+	inner := indent
+	if len(conds) > 0 {
+		w.line(indent, "} else {")
+		inner = indent + "  "
+	}
+
+	switch typ {
+	case "object":
+		w.properties(s, expr, path, inner)
+	case "array":
+		item := fmt.Sprintf("item%d", w.depth)
+		i := fmt.Sprintf("i%d", w.depth)
+		w.depth++
+		w.line(inner, "%s.forEach((%s, %s) => {", expr, item, i)
+		w.check(s["items"].(map[string]any), item, fmt.Sprintf("join(%s, `[${%s}]`)", path, i), inner+"  ")
+		w.line(inner, "});")
+		w.depth--
+	}
+
+	if len(conds) > 0 {
+		w.line(indent, "}")
+	}
+}
+
+func (w *validator) properties(s map[string]any, expr, path, indent string) {
+	props, _ := s["properties"].(map[string]any)
+	required := requiredSet(s)
+	for _, key := range sortedKeys(props) {
+		prop := accessor(expr, key)
+		propPath := fmt.Sprintf("join(%s, %q)", path, key)
+
+		if required[key] {
+			w.line(indent, "if (%s === undefined || %s === null) {", prop, prop)
+			w.line(indent, "  errors[%s] = %q;", propPath, value.CodeNotSet)
+			w.line(indent, "} else {")
+		} else {
+			w.line(indent, "if (%s !== undefined && %s !== null) {", prop, prop)
+		}
+
+		w.check(props[key].(map[string]any), prop, propPath, indent+"  ")
+		w.line(indent, "}")
+	}
+}
+
+// conditions returns the failing conditions of the schema with their codes,
+// in the order they are checked.
+func (w *validator) conditions(s map[string]any, expr string) [][2]string {
+	var conds [][2]string
+	add := func(cond, code string) {
+		conds = append(conds, [2]string{cond, code})
+	}
+
+	if enum, ok := s["enum"].([]any); ok {
+		values := make([]string, 0, len(enum))
+		for _, v := range enum {
+			if v != nil {
+				values = append(values, literal(v))
+			}
+		}
+
+		add(fmt.Sprintf("![%s].includes(%s)", strings.Join(values, ", "), expr), jsonschema.Code(s, "enum"))
+	}
+
+	switch nonNullType(s) {
+	case "string":
+		add(fmt.Sprintf("typeof %s !== \"string\"", expr), value.CodeInvalid)
+		if n, ok := s["minLength"]; ok {
+			add(fmt.Sprintf("[...%s].length < %v", expr, n), jsonschema.Code(s, "minLength"))
+		}
+
+		if n, ok := s["maxLength"]; ok {
+			add(fmt.Sprintf("[...%s].length > %v", expr, n), jsonschema.Code(s, "maxLength"))
+		}
+
+		if pattern, ok := s["pattern"].(string); ok {
+			add(fmt.Sprintf("!new RegExp(%s).test(%s)", literal(pattern), expr), jsonschema.Code(s, "pattern"))
+		}
+	case "integer":
+		add(fmt.Sprintf("!Number.isInteger(%s)", expr), value.CodeInvalid)
+		w.bounds(s, expr, add)
+	case "number":
+		add(fmt.Sprintf("typeof %s !== \"number\"", expr), value.CodeInvalid)
+		w.bounds(s, expr, add)
+	case "boolean":
+		add(fmt.Sprintf("typeof %s !== \"boolean\"", expr), value.CodeInvalid)
+	case "array":
+		add(fmt.Sprintf("!Array.isArray(%s)", expr), value.CodeInvalid)
+		if n, ok := s["minItems"]; ok {
+			add(fmt.Sprintf("%s.length < %v", expr, n), jsonschema.Code(s, "minItems"))
+		}
+
+		if n, ok := s["maxItems"]; ok {
+			add(fmt.Sprintf("%s.length > %v", expr, n), jsonschema.Code(s, "maxItems"))
+		}
+	case "object":
+		add(fmt.Sprintf("typeof %s !== \"object\" || Array.isArray(%s)", expr, expr), value.CodeInvalid)
+	}
+
+	return conds
+}
+
+func (w *validator) bounds(s map[string]any, expr string, add func(cond, code string)) {
+	if n, ok := s["minimum"]; ok {
+		add(fmt.Sprintf("%s < %v", expr, n), jsonschema.Code(s, "minimum"))
+	}
+
+	if n, ok := s["maximum"]; ok {
+		add(fmt.Sprintf("%s > %v", expr, n), jsonschema.Code(s, "maximum"))
+	}
+}
+
+// nonNullType returns the type of the schema, without null.
+func nonNullType(s map[string]any) string {
+	switch typ := s["type"].(type) {
+	case string:
+		return typ
+	case []any:
+		for _, t := range typ {
+			if t != "null" {
+				return t.(string)
+			}
+		}
+	}
+
+	return ""
+}
+
+func requiredSet(s map[string]any) map[string]bool {
+	set := make(map[string]bool)
+	switch required := s["required"].(type) {
+	case []string:
+		for _, key := range required {
+			set[key] = true
+		}
+	case []any:
+		for _, key := range required {
+			set[key.(string)] = true
+		}
+	}
+
+	return set
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// typeName exports the name, e.g. "address" as "Address".
+func typeName(name string) string {
+	if name == "" {
+		return name
+	}
+
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func propertyName(key string) string {
+	if identifier.MatchString(key) {
+		return key
+	}
+
+	return literal(key)
+}
+
+func accessor(expr, key string) string {
+	if identifier.MatchString(key) {
+		return expr + "." + key
+	}
+
+	return expr + "[" + literal(key) + "]"
+}
+
+// literal returns the value as a JavaScript literal.
+func literal(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "undefined"
+	}
+
+	return string(b)
+}
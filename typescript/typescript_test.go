@@ -0,0 +1,133 @@
+package typescript_test
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/alextanhongpin/value"
+	"github.com/alextanhongpin/value/examples/colors"
+	"github.com/alextanhongpin/value/rules"
+	"github.com/alextanhongpin/value/typescript"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+type role string
+
+type address struct {
+	City       *value.Value[string] `json:"city"`
+	PostalCode *value.Value[string] `json:"postal-code" value:"optional"`
+}
+
+func (a *address) Validate() error {
+	return value.ValidateStruct(a)
+}
+
+type createUserDto struct {
+	Email     *value.Value[string]       `json:"email"`
+	Age       *value.Value[int]          `json:"age" value:"optional"`
+	Role      *value.Value[role]         `json:"role"`
+	Color     *value.Object[*colors.RGB] `json:"color" value:"optional"`
+	Addresses []*value.Object[*address]  `json:"addresses"`
+	Nickname  value.Nullable[string]     `json:"nickname" value:"optional"`
+}
+
+func TestDocument(t *testing.T) {
+	doc := typescript.New()
+	doc.Register("CreateUserDto", &createUserDto{
+		Email: value.New("", value.Rules(rules.MaxLen[string](255), rules.Regex[string](`^[^@\s]+@[^@\s]+$`))),
+		Age:   value.New(0, value.Rules(rules.Between(0, 150))),
+		Role:  value.New[role]("", value.Rules[role](value.NewEnum[role]("admin", "member"))),
+		Addresses: []*value.Object[*address]{
+			value.NewObject(&address{
+				City:       value.New("", value.Rules(rules.NotEmpty[string]())),
+				PostalCode: value.New("", value.Rules(rules.Prefix[string]("S"))),
+			}),
+		},
+	})
+
+	var b bytes.Buffer
+	if _, err := doc.WriteTo(&b); err != nil {
+		t.Fatalf("failed to write: %s", err)
+	}
+
+	golden := "testdata/output.golden.ts"
+	if *update {
+		if err := os.WriteFile(golden, b.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(want, b.Bytes()) {
+		t.Fatalf("expected %s, got %s", want, b.Bytes())
+	}
+}
+
+type other struct {
+	Note *value.Value[string] `json:"note"`
+}
+
+func TestDocumentNameClash(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nested struct", func(t *testing.T) {
+		doc := typescript.New()
+		doc.Register("CreateUserDto", &createUserDto{})
+		doc.Register("Address", &other{})
+
+		var b strings.Builder
+		if _, err := doc.WriteTo(&b); err != nil {
+			t.Fatalf("failed to write: %s", err)
+		}
+
+		for _, want := range []string{
+			"addresses?: Address2[];",
+			"export interface Address {\n  note: string;",
+			"export interface Address2 {",
+		} {
+			if !strings.Contains(b.String(), want) {
+				t.Fatalf("expected %s, got %s", want, b.String())
+			}
+		}
+	})
+
+	t.Run("registered type", func(t *testing.T) {
+		doc := typescript.New()
+		doc.Register("CreateUserDto", &createUserDto{})
+		doc.Register("Address", &address{
+			City: value.New("", value.Rules(rules.NotEmpty[string]())),
+		})
+
+		var b strings.Builder
+		if _, err := doc.WriteTo(&b); err != nil {
+			t.Fatalf("failed to write: %s", err)
+		}
+
+		if want := "addresses?: Address[];"; !strings.Contains(b.String(), want) {
+			t.Fatalf("expected %s, got %s", want, b.String())
+		}
+
+		if strings.Contains(b.String(), "Address2") {
+			t.Fatalf("expected no Address2, got %s", b.String())
+		}
+	})
+
+	t.Run("registered names", func(t *testing.T) {
+		doc := typescript.New()
+		doc.Register("CreateUserDto", &createUserDto{})
+		doc.Register("createUserDto", &other{})
+
+		if _, err := doc.WriteTo(&strings.Builder{}); !errors.Is(err, typescript.ErrDuplicateName) {
+			t.Fatalf("expected %s, got %v", typescript.ErrDuplicateName, err)
+		}
+	})
+}
@@ -13,16 +13,30 @@ var (
 )
 
 // Value represents a generic value object.
+//
+// Value is comparable, so it can be used as a map key, but only Values
+// without rules should be: the rules are compared by identity, so two Values
+// with the same value and their own rules are different keys. Copies of a
+// Value share its rules.
 type Value[T any] struct {
 	value T
 	dirty bool
+	// rules are held by pointer, so that Value remains comparable.
+	rules *[]Rule[T]
 }
 
-func New[T any](t T) *Value[T] {
-	return &Value[T]{
+func New[T any](t T, opts ...Option[T]) *Value[T] {
+	// Like NewObject, an invalid value is returned so that the validation can be
+	// deferred.
+	v := &Value[T]{
 		value: t,
 		dirty: true,
 	}
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v
 }
 
 func (v *Value[T]) IsZero() bool {
@@ -30,6 +44,10 @@ func (v *Value[T]) IsZero() bool {
 }
 
 func (v *Value[T]) Set(t T) error {
+	if err := v.validate(t); err != nil {
+		return err
+	}
+
 	v.value = t
 	v.dirty = true
 
@@ -57,7 +75,7 @@ func (v *Value[T]) Validate() error {
 		return ErrNotSet
 	}
 
-	return nil
+	return v.validate(v.value)
 }
 
 func (v *Value[T]) MustValidate() {
@@ -79,7 +97,11 @@ func (v *Value[T]) Optional() bool {
 }
 
 func (v *Value[T]) ValidateOptional() error {
-	return nil
+	if v.IsZero() {
+		return nil
+	}
+
+	return v.validate(v.value)
 }
 
 func (v *Value[T]) String() string {
@@ -108,8 +130,5 @@ func (v *Value[T]) UnmarshalJSON(raw []byte) error {
 		return err
 	}
 
-	v.value = t
-	v.dirty = true
-
-	return nil
+	return v.Set(t)
 }
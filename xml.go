@@ -0,0 +1,181 @@
+package value
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+const xsiNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+
+// MarshalXML implements xml.Marshaler. Values that are not set are omitted.
+func (v Value[T]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if v.IsZero() {
+		return nil
+	}
+
+	return e.EncodeElement(v.value, start)
+}
+
+// UnmarshalXML implements xml.Unmarshaler. Elements with xsi:nil="true" reset
+// the value to not set. The rules attached to the value are enforced.
+func (v *Value[T]) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	if isXMLNil(start) {
+		v.reset()
+
+		return d.Skip()
+	}
+
+	t := Zero[T]()
+	if err := d.DecodeElement(&t, &start); err != nil {
+		return err
+	}
+
+	return v.Set(t)
+}
+
+// MarshalXMLAttr implements xml.MarshalerAttr. Values that are not set are
+// omitted. Unlike MarshalXML, it has a pointer receiver, since encoding/xml
+// calls it on nil pointers.
+func (v *Value[T]) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if v.IsZero() {
+		return xml.Attr{}, nil
+	}
+
+	return marshalXMLAttr(name, v.value)
+}
+
+func (v *Value[T]) UnmarshalXMLAttr(attr xml.Attr) error {
+	return v.UnmarshalText([]byte(attr.Value))
+}
+
+// MarshalXML implements xml.Marshaler. Objects that are not set are omitted.
+func (o *Object[T]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if o.IsZero() {
+		return nil
+	}
+
+	return e.EncodeElement(o.value, start)
+}
+
+// UnmarshalXML implements xml.Unmarshaler. Elements with xsi:nil="true" reset
+// the object to not set.
+//
+// Like UnmarshalJSON, the object is not validated. Use an XMLDecoder with the
+// Eager policy to validate the decoded objects.
+func (o *Object[T]) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	if isXMLNil(start) {
+		o.reset()
+
+		return d.Skip()
+	}
+
+	t := Zero[T]()
+	if err := d.DecodeElement(&t, &start); err != nil {
+		return err
+	}
+
+	o.value = t
+	o.dirty = true
+
+	return nil
+}
+
+// MarshalXMLAttr implements xml.MarshalerAttr. Objects that are not set are
+// omitted.
+func (o *Object[T]) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if o.IsZero() {
+		return xml.Attr{}, nil
+	}
+
+	return marshalXMLAttr(name, o.value)
+}
+
+func (o *Object[T]) UnmarshalXMLAttr(attr xml.Attr) error {
+	return o.UnmarshalText([]byte(attr.Value))
+}
+
+// MarshalXML implements xml.Marshaler. Undefined values are omitted, and null
+// values are encoded with xsi:nil="true".
+func (n Nullable[T]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if n.IsZero() {
+		return nil
+	}
+
+	if n.IsNull() {
+		start.Attr = append(start.Attr,
+			xml.Attr{Name: xml.Name{Local: "xmlns:xsi"}, Value: xsiNamespace},
+			xml.Attr{Name: xml.Name{Local: "xsi:nil"}, Value: "true"},
+		)
+
+		return e.EncodeElement("", start)
+	}
+
+	return e.EncodeElement(n.value, start)
+}
+
+// UnmarshalXML implements xml.Unmarshaler. Elements with xsi:nil="true" are
+// decoded as null.
+func (n *Nullable[T]) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	if isXMLNil(start) {
+		n.SetNull()
+
+		return d.Skip()
+	}
+
+	t := Zero[T]()
+	if err := d.DecodeElement(&t, &start); err != nil {
+		return err
+	}
+
+	return n.Set(t)
+}
+
+// XMLDecoder decodes XML into structs with Value, Object and Nullable fields,
+// and validates them according to the policy.
+type XMLDecoder struct {
+	*xml.Decoder
+
+	Policy Policy
+	report report
+}
+
+func NewXMLDecoder(r io.Reader) *XMLDecoder {
+	return &XMLDecoder{
+		Decoder: xml.NewDecoder(r),
+	}
+}
+
+func (d *XMLDecoder) Decode(v any) error {
+	if err := d.Decoder.Decode(v); err != nil {
+		return err
+	}
+
+	return d.report.apply(d.Policy, v)
+}
+
+// Report returns the errors collected by the Collect policy as Errors with a
+// *DocumentError for each invalid document, or nil if there are none.
+func (d *XMLDecoder) Report() error {
+	return d.report.err()
+}
+
+func marshalXMLAttr(name xml.Name, v any) (xml.Attr, error) {
+	b, err := MarshalText(v)
+	if err != nil {
+		return xml.Attr{}, err
+	}
+
+	return xml.Attr{Name: name, Value: string(b)}, nil
+}
+
+func isXMLNil(start xml.StartElement) bool {
+	for _, attr := range start.Attr {
+		if attr.Name.Local != "nil" || (attr.Name.Space != xsiNamespace && attr.Name.Space != "xsi") {
+			continue
+		}
+
+		return attr.Value == "true" || attr.Value == "1"
+	}
+
+	return false
+}
@@ -0,0 +1,90 @@
+package value_test
+
+import (
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/alextanhongpin/value"
+)
+
+type partner struct {
+	XMLName xml.Name               `xml:"partner"`
+	ID      *value.Value[int]      `xml:"id,attr"`
+	Name    *value.Value[string]   `xml:"name"`
+	Age     *value.Object[*age]    `xml:"age" json:"age"`
+	Note    value.Nullable[string] `xml:"note"`
+	Ref     *value.Value[string]   `xml:"ref,attr"`
+}
+
+func TestXML(t *testing.T) {
+	t.Parallel()
+
+	t.Run("marshal", func(t *testing.T) {
+		t.Parallel()
+
+		p := partner{
+			ID:   value.New(1),
+			Name: &value.Value[string]{},
+			Age:  value.NewObject(newAge(10)),
+			Note: value.Null[string](),
+		}
+
+		b, err := xml.Marshal(p)
+		if err != nil {
+			t.Fatalf("failed to marshal: %s", err)
+		}
+
+		want := `<partner id="1"><age>10</age><note xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:nil="true"></note></partner>`
+		if got := string(b); want != got {
+			t.Fatalf("expected %s, got %s", want, got)
+		}
+
+		var out partner
+		if err := xml.Unmarshal(b, &out); err != nil {
+			t.Fatalf("failed to unmarshal: %s", err)
+		}
+
+		if out.ID.MustGet() != 1 || *out.Age.MustGet() != 10 || !out.Note.IsNull() || out.Name != nil {
+			t.Fatalf("unexpected round trip: %+v", out)
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		t.Parallel()
+
+		raw := `<partner xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"><name xsi:nil="true"/></partner>`
+
+		var out partner
+		if err := xml.Unmarshal([]byte(raw), &out); err != nil {
+			t.Fatalf("failed to unmarshal: %s", err)
+		}
+
+		if !out.Name.IsZero() {
+			t.Fatalf("expected name to be not set, got %s", out.Name)
+		}
+	})
+
+	t.Run("eager", func(t *testing.T) {
+		t.Parallel()
+
+		raw := `<partner id="1" ref="a"><name>john</name><age>-1</age><note>hi</note></partner>`
+
+		var lenient partner
+		if err := value.NewXMLDecoder(strings.NewReader(raw)).Decode(&lenient); err != nil {
+			t.Fatalf("expected lenient decode, got %s", err)
+		}
+
+		dec := value.NewXMLDecoder(strings.NewReader(raw))
+		dec.Policy = value.Eager
+
+		var eager partner
+		err := dec.Decode(&eager)
+
+		var errs value.ErrorMap
+		if !errors.As(err, &errs) || errs["age"] == nil || len(errs) != 1 {
+			t.Fatalf("expected age error, got %v", err)
+		}
+	})
+}